@@ -9,6 +9,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // API has request and response info
@@ -21,12 +22,28 @@ type API struct {
 	RequestURLParams         map[string]string `json:"request_url_params"`
 	RequestPostForms         map[string]string `json:"request_post_forms"`
 	RequestBody              string            `json:"request_body"`
+	RequestScheme            string            `json:"request_scheme"`
+	RequestHost              string            `json:"request_host,omitempty"`
+	RequestProto             string            `json:"request_proto"`
+	RequestRawQuery          string            `json:"request_raw_query"`
+	RequestLine              string            `json:"request_line"`
+	RequestContentLength     int64             `json:"request_content_length"`
+	RequestStartedAt         time.Time         `json:"request_started_at,omitzero"`
 
 	// Response
 	ResponseHeaders           map[string]string `json:"response_headers"`
 	ResponseSuppressedHeaders map[string]bool   `json:"response_suppressed_headers"`
 	ResponseStatusCode        int               `json:"response_status_code"`
 	ResponseBody              string            `json:"response_body"`
+	// Duration is the time handler took, see MeasureDuration
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Tags group apis in documents, e.g. Postman folders
+	Tags []string `json:"tags,omitempty"`
+	// Version is api version like v1, set it explicitly or by Project.Versioner
+	Version string `json:"version,omitempty"`
+	// Examples are other exchanges of same endpoint kept by MergeKeepBoth
+	Examples []API `json:"examples,omitempty"`
 }
 
 // NewAPI new api instance
@@ -41,7 +58,7 @@ func NewAPI() API {
 }
 
 func (a API) equal(a2 API) bool {
-	return a.RequestMethod == a2.RequestMethod && a.RequestPath == a2.RequestPath && a.ResponseStatusCode == a2.ResponseStatusCode
+	return a.Version == a2.Version && a.RequestMethod == a2.RequestMethod && a.RequestPath == a2.RequestPath && a.ResponseStatusCode == a2.ResponseStatusCode
 }
 
 // SuppressedRequestHeaders ignore request headers
@@ -59,7 +76,8 @@ func (a *API) ReadRequestHeader(httpHeader http.Header) error {
 		return err
 	}
 	for _, header := range strings.Split(b.String(), "\n") {
-		values := strings.Split(header, ":")
+		// values like urls and dates contain ":"
+		values := strings.SplitN(header, ":", 2)
 		if len(values) < 2 {
 			continue
 		}
@@ -67,7 +85,7 @@ func (a *API) ReadRequestHeader(httpHeader http.Header) error {
 		if key == "" {
 			continue
 		}
-		a.RequestHeaders[key] = values[1]
+		a.RequestHeaders[key] = strings.TrimSpace(values[1])
 	}
 	return nil
 }
@@ -175,10 +193,27 @@ func (a *API) getRequestURI(req *http.Request) string {
 	return reqURI
 }
 
+func (a *API) getRequestScheme(req *http.Request) string {
+	if req.URL.Scheme != "" {
+		return req.URL.Scheme
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
 // ReadRequest read values from http.Request
 func (a *API) ReadRequest(req *http.Request, throwErr bool) error {
+	a.RequestStartedAt = time.Now()
 	a.RequestMethod = req.Method
 	a.RequestPath = strings.Split(a.getRequestURI(req), "?")[0]
+	a.RequestScheme = a.getRequestScheme(req)
+	a.RequestHost = req.Host
+	a.RequestProto = req.Proto
+	a.RequestRawQuery = req.URL.RawQuery
+	a.RequestLine = req.Method + " " + a.getRequestURI(req) + " " + req.Proto
+	a.RequestContentLength = req.ContentLength
 	if err := a.ReadRequestHeader(req.Header); err != nil {
 		if throwErr {
 			return err
@@ -200,6 +235,14 @@ func (a *API) ReadRequest(req *http.Request, throwErr bool) error {
 	return nil
 }
 
+// MeasureDuration set duration since ReadRequest, call it after handler served
+func (a *API) MeasureDuration() {
+	if a.RequestStartedAt.IsZero() {
+		return
+	}
+	a.Duration = time.Since(a.RequestStartedAt)
+}
+
 // SuppressedResponseHeaders ignore response headers
 func (a *API) SuppressedResponseHeaders(headers ...string) {
 	a.ResponseSuppressedHeaders = make(map[string]bool, len(headers))
@@ -215,7 +258,8 @@ func (a *API) ReadResponseHeader(httpHeader http.Header) error {
 		return err
 	}
 	for _, header := range strings.Split(b.String(), "\n") {
-		values := strings.Split(header, ":")
+		// values like urls and dates contain ":"
+		values := strings.SplitN(header, ":", 2)
 		if len(values) < 2 {
 			continue
 		}
@@ -223,7 +267,7 @@ func (a *API) ReadResponseHeader(httpHeader http.Header) error {
 		if key == "" {
 			continue
 		}
-		a.ResponseHeaders[key] = values[1]
+		a.ResponseHeaders[key] = strings.TrimSpace(values[1])
 	}
 	return nil
 }
@@ -244,3 +288,71 @@ func (a *API) WrapResponseBody(body []byte) error {
 
 	return nil
 }
+
+func (a API) requestContentType() string {
+	if ct := mediaType(a.RequestHeaders["Content-Type"]); ct != "" {
+		return ct
+	}
+	if len(a.RequestPostForms) > 0 {
+		return "application/x-www-form-urlencoded"
+	}
+	if isJSON(a.RequestBody) {
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+func (a API) responseContentType() string {
+	return mediaType(a.ResponseHeaders["Content-Type"])
+}
+
+// origin return recorded scheme and host
+func (a API) origin() string {
+	if a.RequestHost == "" {
+		return ""
+	}
+	scheme := a.RequestScheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	return scheme + "://" + a.RequestHost
+}
+
+// requestURL build request url with baseURL, default is recorded host or http://localhost
+func (a API) requestURL(baseURL string) string {
+	if baseURL == "" {
+		baseURL = a.origin()
+	}
+	if baseURL == "" {
+		baseURL = "http://localhost"
+	}
+	u := strings.TrimRight(baseURL, "/") + a.RequestPath
+	if a.RequestRawQuery != "" {
+		// keep recorded order
+		return u + "?" + a.RequestRawQuery
+	}
+	if len(a.RequestURLParams) == 0 {
+		return u
+	}
+	params := make([]string, 0, len(a.RequestURLParams))
+	for _, key := range sortedKeys(a.RequestURLParams) {
+		params = append(params, key+"="+a.RequestURLParams[key])
+	}
+	return u + "?" + strings.Join(params, "&")
+}
+
+// NewRequest build http.Request reproducing recorded request with baseURL
+func (a API) NewRequest(baseURL string) (*http.Request, error) {
+	var body io.Reader
+	if s := a.requestBodyString(); s != "" {
+		body = strings.NewReader(s)
+	}
+	req, err := http.NewRequest(a.RequestMethod, a.requestURL(baseURL), body)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range a.snippetHeaderKeys() {
+		req.Header.Set(key, headerValue(a.RequestHeaders[key]))
+	}
+	return req, nil
+}
@@ -3,6 +3,7 @@ package apidoc
 import (
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -58,6 +59,32 @@ func TestReadResponseHeader(t *testing.T) {
 	}
 }
 
+func TestReadHeaderValuesWithColon(t *testing.T) {
+	api := NewAPI()
+	api.RequestMethod = "GET"
+	api.RequestPath = "/users"
+	var header http.Header = make(map[string][]string)
+	header.Set("Referer", "http://example.com:8080/users?page=2")
+	if err := api.ReadRequestHeader(header); err != nil {
+		t.Fatal(err)
+	}
+	if api.RequestHeaders["Referer"] != "http://example.com:8080/users?page=2" {
+		t.Fatalf("%q", api.RequestHeaders["Referer"])
+	}
+	if curl := api.CurlCommand(""); !strings.Contains(curl, "-H 'Referer: http://example.com:8080/users?page=2'") {
+		t.Fatal(curl)
+	}
+
+	header = make(map[string][]string)
+	header.Set("Date", "Mon, 02 Jan 2006 15:04:05 GMT")
+	if err := api.ReadResponseHeader(header); err != nil {
+		t.Fatal(err)
+	}
+	if api.ResponseHeaders["Date"] != "Mon, 02 Jan 2006 15:04:05 GMT" {
+		t.Fatalf("%q", api.ResponseHeaders["Date"])
+	}
+}
+
 func TestWrapResponseBody(t *testing.T) {
 	api := NewAPI()
 
@@ -77,3 +104,34 @@ func TestWrapResponseBody(t *testing.T) {
 	}
 	t.Log(api.ResponseBody)
 }
+
+func TestReadRequest(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users?b=2&a=1", strings.NewReader("name=gotokatsuya"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	api := NewAPI()
+	if err := api.ReadRequest(req, true); err != nil {
+		t.Fatal(err)
+	}
+	if api.RequestHost != "example.com" || api.RequestScheme != "http" || api.RequestProto != "HTTP/1.1" {
+		t.Fatalf("unexpected host %s, scheme %s, proto %s", api.RequestHost, api.RequestScheme, api.RequestProto)
+	}
+	if api.RequestRawQuery != "b=2&a=1" {
+		t.Fatal("RequestRawQuery is not equal")
+	}
+	if api.RequestLine != "POST /users?b=2&a=1 HTTP/1.1" {
+		t.Fatal(api.RequestLine)
+	}
+	if api.RequestContentLength != 16 {
+		t.Fatal("RequestContentLength is not 16")
+	}
+	if api.RequestPostForms["name"] != "gotokatsuya" {
+		t.Fatal("name is not equal")
+	}
+	if api.requestURL("") != "http://example.com/users?b=2&a=1" {
+		t.Fatal(api.requestURL(""))
+	}
+	api.MeasureDuration()
+	if api.Duration <= 0 {
+		t.Fatal("Duration is not measured")
+	}
+}
@@ -1,23 +1,35 @@
 package apidoc
 
+import "sync"
+
 var (
 	// disable not gen docs if true
 	disable bool
 
-	// global project
-	p Project
+	// global project, guarded by mu since handlers record apis concurrently
+	p  Project
+	mu sync.Mutex
 )
 
 // Init initialize project setting
+// Missing document json file means no prior apis, corrupt one returns *DocumentError.
+// Apis are loaded from Project.Store if set.
 func Init(newProject Project) error {
+	mu.Lock()
+	defer mu.Unlock()
 	p = newProject
 	p.APIs = []API{}
-	if err := p.loadDocumentJSONFile(); err != nil {
+	p.unsaved = nil
+	if err := p.load(); err != nil {
 		return err
 	}
-	if err := p.writeDocumentFile(); err != nil {
+	if verification {
+		return nil
+	}
+	if err := p.writeOutputFiles(); err != nil {
 		return err
 	}
+	reloads.notify()
 	return nil
 }
 
@@ -38,24 +50,72 @@ func IsDisabled() bool {
 
 // Clear delete all files
 func Clear() error {
-	if err := p.deleteDocumentJSONFile(); err != nil {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := p.getStore().Delete(); err != nil {
 		return err
 	}
-	if err := p.deleteDocumentFile(); err != nil {
+	if err := p.deleteOutputFiles(); err != nil {
 		return err
 	}
 	p.APIs = []API{}
+	p.unsaved = nil
+	reloads.notify()
 	return nil
 }
 
 // Gen generate api document
+// In verification mode, api is compared with recorded api and *DriftError is returned if differ.
+// Documents served by Handler are reloaded after api is recorded, apis under Project.ExcludePaths are ignored.
+// Api is normalized by Project.Normalizers before it is compared or recorded.
 func Gen(api API) error {
-	p.appendAPI(api)
-	if err := p.writeDocumentJSONFile(); err != nil {
-		return err
+	mu.Lock()
+	defer mu.Unlock()
+	return gen(api)
+}
+
+// gen verify or record apis which are not excluded, mu must be held
+func gen(apis ...API) error {
+	var found []Drift
+	recorded := false
+	for _, api := range apis {
+		if p.excludes(api.RequestPath) {
+			continue
+		}
+		api = p.normalize(api)
+		if verification {
+			found = append(found, p.Verify(api)...)
+			continue
+		}
+		p.appendAPI(api)
+		recorded = true
+	}
+	if len(found) > 0 {
+		drifts = append(drifts, found...)
+		return &DriftError{Drifts: found}
 	}
-	if err := p.writeDocumentFile(); err != nil {
+	if !recorded {
+		return nil
+	}
+	if err := p.save(); err != nil {
 		return err
 	}
+	reloads.notify()
 	return nil
 }
+
+// IsExcluded return true if requestPath is under Project.ExcludePaths, so that middleware can skip reading it
+func IsExcluded(requestPath string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return p.excludes(requestPath)
+}
+
+// snapshot return copy of global project
+func snapshot() *Project {
+	mu.Lock()
+	defer mu.Unlock()
+	sp := p
+	sp.APIs = append([]API{}, p.APIs...)
+	return &sp
+}
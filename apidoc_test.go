@@ -1,6 +1,11 @@
 package apidoc
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestInit(t *testing.T) {
 	if err := Init(Project{
@@ -19,3 +24,53 @@ func TestInit(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestInitWithoutDocumentJSONFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := Init(Project{
+		DocumentPath: filepath.Join(dir, "apidoc.html"),
+		TemplatePath: "default.tpl.html",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.APIs) != 0 {
+		t.Fatal("API len is not 0")
+	}
+}
+
+func TestInitWithCorruptDocumentJSONFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	newProject := Project{
+		DocumentPath: filepath.Join(dir, "apidoc.html"),
+		TemplatePath: "default.tpl.html",
+	}
+	if err := ioutil.WriteFile(newProject.getDocumentJSONPath(), []byte("[{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = Init(newProject)
+	docErr, ok := err.(*DocumentError)
+	if !ok || !docErr.Corrupt {
+		t.Fatalf("corrupt DocumentError is expected but got %v", err)
+	}
+
+	newProject.RecoverCorruptDocument = true
+	if err := Init(newProject); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := filepath.Glob(newProject.getDocumentJSONPath() + ".corrupt-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatal("corrupt file is not backed up")
+	}
+}
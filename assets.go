@@ -0,0 +1,30 @@
+package apidoc
+
+import _ "embed"
+
+// defaultTemplate is used if TemplatePath is empty, so that document is rendered without source tree
+//
+//go:embed default.tpl.html
+var defaultTemplate string
+
+// endpointTemplate define "endpoint" template rendering an api, which is available in every html template
+//
+//go:embed endpoint.tpl.html
+var endpointTemplate string
+
+// siteTemplate define pages of WriteSite
+//
+//go:embed site.tpl.html
+var siteTemplate string
+
+// assetCSS and assetJS are inlined into html document, so that it is readable offline
+var (
+	//go:embed assets/apidoc.css
+	assetCSS string
+	//go:embed assets/apidoc.js
+	assetJS string
+	//go:embed assets/site.css
+	assetSiteCSS string
+	//go:embed assets/site.js
+	assetSiteJS string
+)
@@ -0,0 +1,53 @@
+(function () {
+    var search = document.getElementById('search');
+    var switcher = document.getElementById('version-switcher');
+    var endpoints = document.querySelectorAll('.endpoint');
+    var texts = {};
+    endpoints.forEach(function (el) {
+        texts[el.id] = el.getAttribute('data-search').toLowerCase();
+    });
+
+    function filter() {
+        var query = search.value.trim().toLowerCase();
+        var version = switcher ? switcher.value : '';
+        var found = false;
+        document.querySelectorAll('.searchable').forEach(function (el) {
+            var id = el.id || el.getAttribute('data-target');
+            var visible = (version === '' || el.getAttribute('data-version') === version) &&
+                (query === '' || texts[id].indexOf(query) >= 0);
+            el.classList.toggle('hidden', !visible);
+            found = found || visible;
+        });
+        document.querySelectorAll('.group').forEach(function (group) {
+            group.classList.toggle('hidden', !group.querySelector('.searchable:not(.hidden)'));
+        });
+        document.getElementById('no-result').classList.toggle('hidden', found);
+    }
+
+    function openTarget() {
+        var el = location.hash && document.getElementById(decodeURIComponent(location.hash.slice(1)));
+        if (el && el.tagName === 'DETAILS') {
+            el.open = true;
+            el.scrollIntoView();
+        }
+    }
+
+    if (search) {
+        search.addEventListener('input', filter);
+    }
+    if (switcher) {
+        switcher.addEventListener('change', filter);
+    }
+    window.addEventListener('hashchange', openTarget);
+    openTarget();
+
+    document.querySelectorAll('.tabs button').forEach(function (button) {
+        button.addEventListener('click', function () {
+            var body = button.closest('.body');
+            var tab = button.getAttribute('data-tab');
+            body.querySelectorAll(':scope > .tabs > [data-tab], :scope > .tab-pane').forEach(function (el) {
+                el.classList.toggle('active', el.getAttribute('data-tab') === tab);
+            });
+        });
+    });
+})();
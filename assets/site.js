@@ -0,0 +1,40 @@
+(function () {
+    var search = document.getElementById('site-search');
+    var results = document.getElementById('site-search-results');
+    var root = document.body.getAttribute('data-root');
+    var entries = null;
+
+    function render(query) {
+        results.innerHTML = '';
+        if (query === '') {
+            return;
+        }
+        entries.filter(function (e) {
+            return e.text.indexOf(query) >= 0;
+        }).slice(0, 50).forEach(function (e) {
+            var li = document.createElement('li');
+            var a = document.createElement('a');
+            a.href = root + e.url;
+            a.textContent = e.title;
+            li.appendChild(a);
+            results.appendChild(li);
+        });
+    }
+
+    search.addEventListener('input', function () {
+        var query = search.value.trim().toLowerCase();
+        if (entries) {
+            render(query);
+            return;
+        }
+        fetch(root + 'search-index.json').then(function (res) {
+            return res.json();
+        }).then(function (index) {
+            entries = index.map(function (e) {
+                e.text = (e.title + ' ' + e.text).toLowerCase();
+                return e;
+            });
+            render(search.value.trim().toLowerCase());
+        });
+    });
+})();
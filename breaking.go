@@ -0,0 +1,31 @@
+package apidoc
+
+// Breaking report whether change may break existing clients
+// Request fields, query params and form fields are recorded from examples, so added ones are regarded as required.
+func (c Change) Breaking() bool {
+	switch c.Kind {
+	case EndpointRemoved,
+		ResponseFieldRemoved,
+		ResponseFieldTypeChanged,
+		RequestFieldAdded,
+		RequestFieldTypeChanged,
+		QueryParamAdded,
+		FormFieldAdded:
+		return true
+	case StatusCodeRemoved:
+		// clients rely on success responses
+		return c.StatusCode >= 200 && c.StatusCode < 300
+	}
+	return false
+}
+
+// BreakingChanges return breaking changes only
+func BreakingChanges(changes []Change) []Change {
+	var breaking []Change
+	for _, c := range changes {
+		if c.Breaking() {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
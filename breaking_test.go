@@ -0,0 +1,35 @@
+package apidoc
+
+import "testing"
+
+func TestChangeBreaking(t *testing.T) {
+	for _, tt := range []struct {
+		change   Change
+		breaking bool
+	}{
+		{Change{Kind: EndpointAdded}, false},
+		{Change{Kind: EndpointRemoved}, true},
+		{Change{Kind: StatusCodeRemoved, StatusCode: 200}, true},
+		{Change{Kind: StatusCodeRemoved, StatusCode: 404}, false},
+		{Change{Kind: ResponseFieldAdded}, false},
+		{Change{Kind: ResponseFieldRemoved}, true},
+		{Change{Kind: ResponseFieldTypeChanged}, true},
+		{Change{Kind: RequestFieldAdded}, true},
+		{Change{Kind: RequestFieldRemoved}, false},
+		{Change{Kind: ResponseHeaderAdded}, false},
+		{Change{Kind: QueryParamAdded}, true},
+		{Change{Kind: FormFieldAdded}, true},
+		{Change{Kind: FormFieldRemoved}, false},
+	} {
+		if tt.change.Breaking() != tt.breaking {
+			t.Fatalf("%s: breaking must be %v", tt.change.Kind, tt.breaking)
+		}
+	}
+}
+
+func TestBreakingChanges(t *testing.T) {
+	changes := []Change{{Kind: EndpointAdded}, {Kind: EndpointRemoved}}
+	if breaking := BreakingChanges(changes); len(breaking) != 1 || breaking[0].Kind != EndpointRemoved {
+		t.Fatalf("unexpected breaking changes %v", breaking)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+func runBreaking(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("breaking", flag.ContinueOnError)
+	format := fs.String("format", "text", "output format, text or markdown")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("old.json and new.json are required")
+	}
+	oldAPIs, err := apidoc.LoadAPIs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	newAPIs, err := apidoc.LoadAPIs(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	changes := apidoc.Diff(oldAPIs, newAPIs)
+	if err := writeDiff(stdout, *format, changes); err != nil {
+		return err
+	}
+	if n := len(apidoc.BreakingChanges(changes)); n > 0 {
+		return fmt.Errorf("%d breaking changes found", n)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+type projectFlags struct {
+	title   *string
+	baseURL *string
+	sortBy  *string
+}
+
+func addProjectFlags(fs *flag.FlagSet) projectFlags {
+	return projectFlags{
+		title:   fs.String("title", "API Doc", "document title"),
+		baseURL: fs.String("base-url", "", "base url used in requests"),
+		sortBy:  fs.String("sort", "path", "order of apis, path, tag or first-seen"),
+	}
+}
+
+// project return project without apis
+func (f projectFlags) project() (apidoc.Project, error) {
+	sortBy, err := apidoc.ParseSortBy(*f.sortBy)
+	if err != nil {
+		return apidoc.Project{}, err
+	}
+	return apidoc.Project{
+		DocumentTitle: *f.title,
+		BaseURL:       *f.baseURL,
+		SortBy:        sortBy,
+		APIs:          []apidoc.API{},
+	}, nil
+}
+
+func (f projectFlags) load(filePath string) (apidoc.Project, error) {
+	p, err := f.project()
+	if err != nil {
+		return apidoc.Project{}, err
+	}
+	apis, err := apidoc.LoadAPIs(filePath)
+	if err != nil {
+		return apidoc.Project{}, err
+	}
+	apidoc.SortAPIs(apis, p.SortBy)
+	p.APIs = apis
+	return p, nil
+}
+
+// writeOutput write to file if filePath is not empty, otherwise stdout
+func writeOutput(stdout io.Writer, filePath string, write func(w io.Writer) error) error {
+	if filePath == "" {
+		return write(stdout)
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func runRender(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	pf := addProjectFlags(fs)
+	templatePath := fs.String("template", "", "html template path, default template if empty")
+	out := fs.String("o", "", "output file, stdout if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("apidoc.json is required")
+	}
+	p, err := pf.load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return writeOutput(stdout, *out, func(w io.Writer) error {
+		return p.WriteHTML(w, *templatePath)
+	})
+}
+
+func runConvert(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	pf := addProjectFlags(fs)
+	format := fs.String("format", "markdown", "output format, html, markdown, openapi, postman, har, json or site")
+	templatePath := fs.String("template", "", "html template path, default template if empty")
+	version := fs.String("version", "", "export apis of the version only")
+	out := fs.String("o", "", "output file, stdout if empty, or directory of site")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("apidoc.json is required")
+	}
+	p, err := pf.load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	vp := &p
+	if *version != "" {
+		vp = p.ForVersion(*version)
+	}
+	if apidoc.Format(*format) == apidoc.FormatSite {
+		if *out == "" {
+			return errors.New("-o directory is required for site")
+		}
+		return vp.WriteSite(*out)
+	}
+	return writeOutput(stdout, *out, func(w io.Writer) error {
+		return vp.Render(w, apidoc.Output{
+			Format:       apidoc.Format(*format),
+			TemplatePath: *templatePath,
+		})
+	})
+}
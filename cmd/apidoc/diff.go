@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+func runDiff(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := fs.String("format", "text", "output format, text or markdown")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("old.json and new.json are required")
+	}
+	oldAPIs, err := apidoc.LoadAPIs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	newAPIs, err := apidoc.LoadAPIs(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	return writeDiff(stdout, *format, apidoc.Diff(oldAPIs, newAPIs))
+}
+
+func writeDiff(w io.Writer, format string, changes []apidoc.Change) error {
+	switch format {
+	case "text":
+		return apidoc.WriteDiffText(w, changes)
+	case "markdown":
+		return apidoc.WriteDiffMarkdown(w, changes)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
@@ -0,0 +1,89 @@
+// Command apidoc works with document json files written by apidoc.Gen.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"breaking": {
+		usage: "breaking [-format text|markdown] old.json new.json",
+		run:   runBreaking,
+	},
+	"convert": {
+		usage: "convert [-format markdown|openapi|postman|har|json|html|site] [-version v1] [-title title] [-base-url url] [-sort path|tag|first-seen] [-o file|dir] apidoc.json",
+		run:   runConvert,
+	},
+	"diff": {
+		usage: "diff [-format text|markdown] old.json new.json",
+		run:   runDiff,
+	},
+	"merge": {
+		usage: "merge [-policy latest|keep-both|fail] [-sort path|tag|first-seen] [-o file] [-html file] [-template file] apidoc.json...",
+		run:   runMerge,
+	},
+	"mock": {
+		usage: "mock [-addr :8080] [-match-query] [-match-body] apidoc.json",
+		run:   runMock,
+	},
+	"render": {
+		usage: "render [-template file] [-title title] [-base-url url] [-sort path|tag|first-seen] [-o file] apidoc.json",
+		run:   runRender,
+	},
+	"replay": {
+		usage: "replay [-base-url http://localhost:8080] apidoc.json",
+		run:   runReplay,
+	},
+	"serve": {
+		usage: "serve [-addr :8080] [-template file] [-title title] apidoc.json",
+		run:   runServe,
+	},
+	"validate": {
+		usage: "validate apidoc.json...",
+		run:   runValidate,
+	},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: apidoc <command> [arguments]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  apidoc %s\n", commands[name].usage)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "apidoc: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	if err := cmd.run(args[1:], stdout); err != nil {
+		fmt.Fprintf(stderr, "apidoc %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+func newTestAPI(path string, status int, body string) apidoc.API {
+	api := apidoc.NewAPI()
+	api.RequestMethod = "GET"
+	api.RequestPath = path
+	api.ResponseHeaders["Content-Type"] = "application/json"
+	api.ResponseStatusCode = status
+	api.ResponseBody = body
+	return api
+}
+
+func writeAPIsFile(t *testing.T, dir, name string, apis ...apidoc.API) string {
+	filePath := filepath.Join(dir, name)
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	p := apidoc.Project{APIs: apis}
+	if err := p.WriteJSON(file); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"unknown"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("exit code is %d", code)
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	oldPath := writeAPIsFile(t, dir, "old.json", newTestAPI("/users", 200, `{"id": 1}`))
+	newPath := writeAPIsFile(t, dir, "new.json", newTestAPI("/users", 200, `{"id": "1"}`))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"diff", "-format", "markdown", oldPath, newPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "response field type changed") {
+		t.Fatal(stdout.String())
+	}
+}
+
+func TestRunBreaking(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	oldPath := writeAPIsFile(t, dir, "old.json", newTestAPI("/users", 200, `{"id": 1}`))
+	addedPath := writeAPIsFile(t, dir, "added.json", newTestAPI("/users", 200, `{"id": 1, "name": "test"}`))
+	removedPath := writeAPIsFile(t, dir, "removed.json", newTestAPI("/users", 200, `{}`))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"breaking", oldPath, addedPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	if code := run([]string{"breaking", oldPath, removedPath}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code is %d", code)
+	}
+	if !strings.Contains(stdout.String(), "[breaking] GET /users 200: response field removed $.id") {
+		t.Fatal(stdout.String())
+	}
+}
+
+func TestRunReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	defer ts.Close()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	filePath := writeAPIsFile(t, dir, "apidoc.json", newTestAPI("/users", 200, `{"id": 1}`))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"replay", "-base-url", ts.URL, filePath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s%s", code, stdout.String(), stderr.String())
+	}
+}
+
+func TestRunConvert(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	filePath := writeAPIsFile(t, dir, "apidoc.json", newTestAPI("/users", 200, `{"id": 1}`))
+	out := filepath.Join(dir, "apidoc.md")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"convert", "-format", "markdown", "-title", "users", "-o", out, filePath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "# users\n\n## GET /users") {
+		t.Fatal(string(b))
+	}
+	if code := run([]string{"convert", "-format", "unknown", filePath}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code is %d", code)
+	}
+}
+
+func TestRunConvertSite(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	filePath := writeAPIsFile(t, dir, "apidoc.json", newTestAPI("/users", 200, `{"id": 1}`))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"convert", "-format", "site", filePath}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code is %d", code)
+	}
+	site := filepath.Join(dir, "site")
+	if code := run([]string{"convert", "-format", "site", "-o", site, filePath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(site, "endpoints", "get-users-200.html")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunRender(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	filePath := writeAPIsFile(t, dir, "apidoc.json", newTestAPI("/users", 200, `{"id": 1}`))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"render", "-template", "../../default.tpl.html", filePath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `href="#get-users-200"`) {
+		t.Fatal(stdout.String())
+	}
+}
+
+func TestRunMerge(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	p1 := writeAPIsFile(t, dir, "1.json", newTestAPI("/users", 200, `{"id": 1}`))
+	p2 := writeAPIsFile(t, dir, "2.json", newTestAPI("/users", 200, `{"id": 2}`), newTestAPI("/items", 200, `{}`))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"merge", p1, p2}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	apis, err := apidoc.ReadAPIs(&stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 2 || apis[1].RequestPath != "/users" || apis[1].ResponseBody != `{"id": 2}` {
+		t.Fatalf("unexpected apis %v", apis)
+	}
+
+	if code := run([]string{"merge", "-policy", "fail", p1, p2}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code is %d", code)
+	}
+
+	html := filepath.Join(dir, "apidoc.html")
+	stdout.Reset()
+	if code := run([]string{"merge", "-policy", "keep-both", "-html", html, "-template", "../../default.tpl.html", p1, p2}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	if apis, err = apidoc.ReadAPIs(&stdout); err != nil || len(apis) != 2 {
+		t.Fatalf("unexpected apis %v %v", apis, err)
+	}
+	if _, err := os.Stat(html); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunMergeKeepBothThenValidate(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	p1 := writeAPIsFile(t, dir, "1.json", newTestAPI("/users", 200, `{"id": 1}`))
+	p2 := writeAPIsFile(t, dir, "2.json", newTestAPI("/users", 200, `{"id": 2}`))
+	merged := filepath.Join(dir, "merged.json")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"merge", "-policy", "keep-both", "-o", merged, p1, p2}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	if code := run([]string{"validate", merged}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	apis, err := apidoc.LoadAPIs(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 1 || len(apis[0].Examples) != 1 || apis[0].Examples[0].ResponseBody != `{"id": 2}` {
+		t.Fatalf("unexpected apis %+v", apis)
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	valid := writeAPIsFile(t, dir, "valid.json", newTestAPI("/users", 200, `{"id": 1}`))
+	invalid := writeAPIsFile(t, dir, "invalid.json", newTestAPI("/users", 0, `{`))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", valid}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code is %d: %s", code, stderr.String())
+	}
+	if code := run([]string{"validate", valid, invalid}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code is %d", code)
+	}
+	if !strings.Contains(stdout.String(), "response body is not valid json") {
+		t.Fatal(stdout.String())
+	}
+}
+
+func TestDocsHandler(t *testing.T) {
+	h := docsHandler{
+		load: func() (apidoc.Project, error) {
+			return apidoc.Project{APIs: []apidoc.API{newTestAPI("/users", 200, `{"id": 1}`)}}, nil
+		},
+		templatePath: "../../default.tpl.html",
+	}
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+	for path, contentType := range map[string]string{
+		"/":            "text/html; charset=utf-8",
+		"/apidoc.json": "application/json; charset=utf-8",
+	} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 200 || resp.Header.Get("Content-Type") != contentType {
+			t.Fatalf("%s: %d %s", path, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+	}
+}
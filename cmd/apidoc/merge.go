@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+func runMerge(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	pf := addProjectFlags(fs)
+	policy := fs.String("policy", "latest", "conflict policy, latest, keep-both or fail")
+	out := fs.String("o", "", "output file, stdout if empty")
+	html := fs.String("html", "", "also render merged html document to file")
+	templatePath := fs.String("template", "", "html template path, default template if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("apidoc.json files are required")
+	}
+	mp, err := apidoc.ParseMergePolicy(*policy)
+	if err != nil {
+		return err
+	}
+	p, err := pf.project()
+	if err != nil {
+		return err
+	}
+	if err := p.MergeFiles(mp, fs.Args()...); err != nil {
+		return err
+	}
+	if err := writeOutput(stdout, *out, p.WriteJSON); err != nil {
+		return err
+	}
+	if *html == "" {
+		return nil
+	}
+	return writeOutput(stdout, *html, func(w io.Writer) error {
+		return p.WriteHTML(w, *templatePath)
+	})
+}
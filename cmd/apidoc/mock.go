@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+func runMock(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("mock", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "listen address")
+	matchQuery := fs.Bool("match-query", false, "require same url params")
+	matchBody := fs.Bool("match-body", false, "require same request body")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("apidoc.json is required")
+	}
+	apis, err := apidoc.LoadAPIs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	h := apidoc.NewMockHandler(apis)
+	h.MatchQuery = *matchQuery
+	h.MatchBody = *matchBody
+	fmt.Fprintf(stdout, "Serving %d mock apis on %s\n", len(apis), *addr)
+	return http.ListenAndServe(*addr, h)
+}
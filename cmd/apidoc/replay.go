@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+func runReplay(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	baseURL := fs.String("base-url", "http://localhost:8080", "base url of target server")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("apidoc.json is required")
+	}
+	apis, err := apidoc.LoadAPIs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	r := apidoc.Replayer{BaseURL: *baseURL}
+	failed := 0
+	for _, result := range r.Replay(apis) {
+		api := result.Expected
+		if result.OK() {
+			fmt.Fprintf(stdout, "ok   %s %s\n", api.RequestMethod, api.RequestPath)
+			continue
+		}
+		failed++
+		fmt.Fprintf(stdout, "FAIL %s %s\n", api.RequestMethod, api.RequestPath)
+		if result.Err != nil {
+			fmt.Fprintf(stdout, "    %v\n", result.Err)
+		}
+		for _, d := range result.Drifts {
+			fmt.Fprintf(stdout, "    %s\n", d)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d apis drifted", failed, len(apis))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+// docsHandler render document from file on each request, so that updates are shown on reload
+type docsHandler struct {
+	load         func() (apidoc.Project, error)
+	templatePath string
+}
+
+func (h docsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p, err := h.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	o := apidoc.Output{Format: apidoc.FormatHTML, TemplatePath: h.templatePath}
+	contentType := "text/html; charset=utf-8"
+	if r.URL.Path == "/apidoc.json" {
+		o.Format = apidoc.FormatJSON
+		contentType = "application/json; charset=utf-8"
+	}
+	var b bytes.Buffer
+	if err := p.Render(&b, o); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	b.WriteTo(w)
+}
+
+func runServe(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	pf := addProjectFlags(fs)
+	addr := fs.String("addr", ":8080", "listen address")
+	templatePath := fs.String("template", "", "html template path, default template if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("apidoc.json is required")
+	}
+	filePath := fs.Arg(0)
+	h := docsHandler{
+		load: func() (apidoc.Project, error) {
+			return pf.load(filePath)
+		},
+		templatePath: *templatePath,
+	}
+	fmt.Fprintf(stdout, "Serving %s on %s\n", filePath, *addr)
+	return http.ListenAndServe(*addr, h)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/gotokatsuya/apidoc"
+)
+
+func runValidate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("apidoc.json files are required")
+	}
+	invalid := 0
+	for _, filePath := range fs.Args() {
+		apis, err := apidoc.LoadAPIs(filePath)
+		if err != nil {
+			invalid++
+			fmt.Fprintf(stdout, "%s: %v\n", filePath, err)
+			continue
+		}
+		errs := apidoc.Validate(apis)
+		for _, err := range errs {
+			fmt.Fprintf(stdout, "%s: %v\n", filePath, err)
+		}
+		if len(errs) > 0 {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files are invalid", invalid, fs.NArg())
+	}
+	return nil
+}
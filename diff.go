@@ -0,0 +1,273 @@
+package apidoc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind kind of change between two documents
+type ChangeKind string
+
+const (
+	// EndpointAdded method and path is added
+	EndpointAdded ChangeKind = "endpoint added"
+	// EndpointRemoved method and path is removed
+	EndpointRemoved ChangeKind = "endpoint removed"
+	// StatusCodeAdded response status code is added
+	StatusCodeAdded ChangeKind = "status code added"
+	// StatusCodeRemoved response status code is removed
+	StatusCodeRemoved ChangeKind = "status code removed"
+	// RequestHeaderAdded request header is added
+	RequestHeaderAdded ChangeKind = "request header added"
+	// RequestHeaderRemoved request header is removed
+	RequestHeaderRemoved ChangeKind = "request header removed"
+	// ResponseHeaderAdded response header is added
+	ResponseHeaderAdded ChangeKind = "response header added"
+	// ResponseHeaderRemoved response header is removed
+	ResponseHeaderRemoved ChangeKind = "response header removed"
+	// QueryParamAdded url param is added
+	QueryParamAdded ChangeKind = "query param added"
+	// QueryParamRemoved url param is removed
+	QueryParamRemoved ChangeKind = "query param removed"
+	// FormFieldAdded post form field is added
+	FormFieldAdded ChangeKind = "form field added"
+	// FormFieldRemoved post form field is removed
+	FormFieldRemoved ChangeKind = "form field removed"
+	// RequestFieldAdded request body field is added
+	RequestFieldAdded ChangeKind = "request field added"
+	// RequestFieldRemoved request body field is removed
+	RequestFieldRemoved ChangeKind = "request field removed"
+	// RequestFieldTypeChanged request body field type is changed
+	RequestFieldTypeChanged ChangeKind = "request field type changed"
+	// ResponseFieldAdded response body field is added
+	ResponseFieldAdded ChangeKind = "response field added"
+	// ResponseFieldRemoved response body field is removed
+	ResponseFieldRemoved ChangeKind = "response field removed"
+	// ResponseFieldTypeChanged response body field type is changed
+	ResponseFieldTypeChanged ChangeKind = "response field type changed"
+)
+
+// Change has a difference between two documents
+type Change struct {
+	Kind ChangeKind
+	// Version is api version, empty if apis are not versioned
+	Version    string
+	Method     string
+	Path       string
+	StatusCode int
+	// Name is header name or json field path like $.users[].id
+	Name string
+	Old  string
+	New  string
+}
+
+func (c Change) String() string {
+	s := c.endpoint()
+	if c.StatusCode != 0 {
+		s += " " + strconv.Itoa(c.StatusCode)
+	}
+	s += ": " + string(c.Kind)
+	if c.Name != "" {
+		s += " " + c.Name
+	}
+	switch {
+	case c.Old != "" && c.New != "":
+		s += fmt.Sprintf(" (%s -> %s)", c.Old, c.New)
+	case c.Old != "":
+		s += fmt.Sprintf(" (%s)", c.Old)
+	case c.New != "":
+		s += fmt.Sprintf(" (%s)", c.New)
+	}
+	return s
+}
+
+// endpoint return method and path, prefixed with version if versioned
+func (c Change) endpoint() string {
+	if c.Version != "" {
+		return c.Version + " " + c.Method + " " + c.Path
+	}
+	return c.Method + " " + c.Path
+}
+
+type endpoint struct {
+	version string
+	method  string
+	path    string
+}
+
+func groupByEndpoint(apis []API) (map[endpoint]map[int]API, []endpoint) {
+	groups := map[endpoint]map[int]API{}
+	var endpoints []endpoint
+	for _, api := range apis {
+		e := endpoint{version: api.Version, method: api.RequestMethod, path: api.RequestPath}
+		if _, ok := groups[e]; !ok {
+			groups[e] = map[int]API{}
+			endpoints = append(endpoints, e)
+		}
+		groups[e][api.ResponseStatusCode] = api
+	}
+	return groups, endpoints
+}
+
+func sortEndpoints(endpoints []endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].path != endpoints[j].path {
+			return endpoints[i].path < endpoints[j].path
+		}
+		if endpoints[i].method != endpoints[j].method {
+			return endpoints[i].method < endpoints[j].method
+		}
+		return endpoints[i].version < endpoints[j].version
+	})
+}
+
+func sortedStatusCodes(m map[int]API) []int {
+	codes := make([]int, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// Diff return changes from old apis to new apis
+func Diff(oldAPIs, newAPIs []API) []Change {
+	oldGroups, oldEndpoints := groupByEndpoint(oldAPIs)
+	newGroups, newEndpoints := groupByEndpoint(newAPIs)
+
+	endpoints := oldEndpoints
+	for _, e := range newEndpoints {
+		if _, ok := oldGroups[e]; !ok {
+			endpoints = append(endpoints, e)
+		}
+	}
+	sortEndpoints(endpoints)
+
+	var changes []Change
+	for _, e := range endpoints {
+		olds, inOld := oldGroups[e]
+		news, inNew := newGroups[e]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Kind: EndpointAdded, Version: e.version, Method: e.method, Path: e.path})
+			continue
+		case !inNew:
+			changes = append(changes, Change{Kind: EndpointRemoved, Version: e.version, Method: e.method, Path: e.path})
+			continue
+		}
+		for _, code := range sortedStatusCodes(olds) {
+			if _, ok := news[code]; !ok {
+				changes = append(changes, Change{Kind: StatusCodeRemoved, Version: e.version, Method: e.method, Path: e.path, StatusCode: code})
+			}
+		}
+		for _, code := range sortedStatusCodes(news) {
+			oldAPI, ok := olds[code]
+			if !ok {
+				changes = append(changes, Change{Kind: StatusCodeAdded, Version: e.version, Method: e.method, Path: e.path, StatusCode: code})
+				continue
+			}
+			changes = append(changes, diffAPI(oldAPI, news[code])...)
+		}
+	}
+	return changes
+}
+
+func diffAPI(oldAPI, newAPI API) []Change {
+	var changes []Change
+	add := func(kind ChangeKind, name, o, n string) {
+		changes = append(changes, Change{
+			Kind:       kind,
+			Version:    newAPI.Version,
+			Method:     newAPI.RequestMethod,
+			Path:       newAPI.RequestPath,
+			StatusCode: newAPI.ResponseStatusCode,
+			Name:       name,
+			Old:        o,
+			New:        n,
+		})
+	}
+	diffKeys := func(o, n map[string]string, added, removed ChangeKind) {
+		for _, key := range sortedKeys(o) {
+			if _, ok := n[key]; !ok {
+				add(removed, key, "", "")
+			}
+		}
+		for _, key := range sortedKeys(n) {
+			if _, ok := o[key]; !ok {
+				add(added, key, "", "")
+			}
+		}
+	}
+	diffFields := func(o, n string, added, removed, typeChanged ChangeKind) {
+		oldShape, _ := jsonShape(o)
+		newShape, _ := jsonShape(n)
+		for _, path := range sortedKeys(oldShape) {
+			t, ok := newShape[path]
+			switch {
+			case !ok:
+				add(removed, path, oldShape[path], "")
+			case t != oldShape[path]:
+				add(typeChanged, path, oldShape[path], t)
+			}
+		}
+		for _, path := range sortedKeys(newShape) {
+			if _, ok := oldShape[path]; !ok {
+				add(added, path, "", newShape[path])
+			}
+		}
+	}
+
+	diffKeys(oldAPI.RequestHeaders, newAPI.RequestHeaders, RequestHeaderAdded, RequestHeaderRemoved)
+	diffKeys(oldAPI.RequestURLParams, newAPI.RequestURLParams, QueryParamAdded, QueryParamRemoved)
+	diffKeys(oldAPI.RequestPostForms, newAPI.RequestPostForms, FormFieldAdded, FormFieldRemoved)
+	diffFields(oldAPI.RequestBody, newAPI.RequestBody, RequestFieldAdded, RequestFieldRemoved, RequestFieldTypeChanged)
+	diffKeys(oldAPI.ResponseHeaders, newAPI.ResponseHeaders, ResponseHeaderAdded, ResponseHeaderRemoved)
+	diffFields(oldAPI.ResponseBody, newAPI.ResponseBody, ResponseFieldAdded, ResponseFieldRemoved, ResponseFieldTypeChanged)
+	return changes
+}
+
+// WriteDiffText write changes as plain text to w
+func WriteDiffText(w io.Writer, changes []Change) error {
+	bw := bufio.NewWriter(w)
+	if len(changes) == 0 {
+		fmt.Fprintln(bw, "No changes")
+	}
+	for _, c := range changes {
+		if c.Breaking() {
+			fmt.Fprint(bw, "[breaking] ")
+		}
+		fmt.Fprintln(bw, c)
+	}
+	return bw.Flush()
+}
+
+// WriteDiffMarkdown write changes as markdown table to w
+func WriteDiffMarkdown(w io.Writer, changes []Change) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "## API Changes\n\n")
+	if len(changes) == 0 {
+		fmt.Fprint(bw, "No changes\n")
+		return bw.Flush()
+	}
+	fmt.Fprint(bw, "| Endpoint | Status | Change | Name | Old | New | Breaking |\n| --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, c := range changes {
+		status := ""
+		if c.StatusCode != 0 {
+			status = strconv.Itoa(c.StatusCode)
+		}
+		breaking := ""
+		if c.Breaking() {
+			breaking = "yes"
+		}
+		cells := []string{c.endpoint(), status, string(c.Kind), c.Name, c.Old, c.New, breaking}
+		for i, cell := range cells {
+			cells[i] = escapeMarkdownCell(cell)
+		}
+		fmt.Fprintf(bw, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,80 @@
+package apidoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a1 := newTestAPI()
+	a2 := newTestAPI()
+	a2.RequestPath = "/items"
+	oldAPIs := []API{a1, a2}
+
+	b1 := newTestAPI()
+	b1.ResponseHeaders["X-Total"] = "1"
+	b1.ResponseBody = `{"users": [{"id": "1", "email": "a@example.com"}]}`
+	b2 := newTestAPI()
+	b2.ResponseStatusCode = 404
+	b3 := newTestAPI()
+	b3.RequestPath = "/orders"
+	newAPIs := []API{b1, b2, b3}
+
+	changes := Diff(oldAPIs, newAPIs)
+	want := []string{
+		"GET /items: endpoint removed",
+		"GET /orders: endpoint added",
+		"GET /users 200: response header added X-Total",
+		"GET /users 200: response field type changed $.users[].id (number -> string)",
+		"GET /users 200: response field removed $.users[].name (string)",
+		"GET /users 200: response field added $.users[].email (string)",
+		"GET /users 404: status code added",
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	for i, c := range changes {
+		if c.String() != want[i] {
+			t.Fatalf("got %q, want %q", c.String(), want[i])
+		}
+	}
+}
+
+func TestDiffFormFields(t *testing.T) {
+	oldAPI := newTestAPI()
+	oldAPI.RequestMethod = "POST"
+	oldAPI.RequestPostForms = map[string]string{"name": "test"}
+	newAPI := newTestAPI()
+	newAPI.RequestMethod = "POST"
+	newAPI.RequestPostForms = map[string]string{"name": "test", "email": "a%40example.com"}
+	newAPI.RequestURLParams = map[string]string{}
+
+	changes := Diff([]API{oldAPI}, []API{newAPI})
+	want := []string{
+		"POST /users 200: query param removed limit",
+		"POST /users 200: form field added email",
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	for i, c := range changes {
+		if c.String() != want[i] {
+			t.Fatalf("got %q, want %q", c.String(), want[i])
+		}
+	}
+	if breaking := BreakingChanges(changes); len(breaking) != 1 || breaking[0].Kind != FormFieldAdded {
+		t.Fatalf("added form field must be breaking %v", breaking)
+	}
+}
+
+func TestWriteDiffMarkdown(t *testing.T) {
+	changes := Diff([]API{newTestAPI()}, []API{})
+	var b bytes.Buffer
+	if err := WriteDiffMarkdown(&b, changes); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "| GET /users |  | endpoint removed |  |  |  | yes |") {
+		t.Fatal(b.String())
+	}
+}
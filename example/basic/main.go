@@ -25,6 +25,8 @@ func apidocMiddleware(handler http.Handler) http.Handler {
 
 		handler.ServeHTTP(recorder, r)
 
+		api.MeasureDuration()
+
 		api.SuppressedResponseHeaders("Cache-Control", "Content-Length", "X-Request-Id", "X-Runtime", "X-XSS-Protection", "ETag")
 		api.ReadResponseHeader(recorder.Header())
 		api.WrapResponseBody(recorder.Body.Bytes())
@@ -52,6 +52,8 @@ func apidocMiddleware(c *gin.Context) {
 
 	// after processing request
 
+	api.MeasureDuration()
+
 	api.SuppressedResponseHeaders("Cache-Control", "Content-Length", "X-Request-Id", "X-Runtime", "X-XSS-Protection", "ETag")
 	api.ReadResponseHeader(c.Writer.Header())
 	api.WrapResponseBody(gbw.Body())
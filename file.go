@@ -0,0 +1,95 @@
+package apidoc
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic write to temp file in same directory and rename it to filePath,
+// so that readers never see partially written file
+func writeFileAtomic(filePath string, write func(w io.Writer) error) error {
+	filePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// lockFile take advisory lock on filePath.lock, call returned func to unlock
+// Lock file is removed on unlock, so it is retried if it was removed while waiting.
+func lockFile(filePath string) (func() error, error) {
+	filePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filePath + ".lock"
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return nil, err
+		}
+		if err := flock(file); err != nil {
+			file.Close()
+			return nil, err
+		}
+		same, err := sameFile(file, lockPath)
+		if err != nil {
+			funlock(file)
+			file.Close()
+			return nil, err
+		}
+		if same {
+			return func() error {
+				// remove before unlock, so that waiters see it is gone
+				removeErr := os.Remove(lockPath)
+				if err := funlock(file); err != nil {
+					file.Close()
+					return err
+				}
+				if err := file.Close(); err != nil {
+					return err
+				}
+				return removeErr
+			}, nil
+		}
+		funlock(file)
+		file.Close()
+	}
+}
+
+// sameFile return true if file is still at filePath
+func sameFile(file *os.File, filePath string) (bool, error) {
+	fi, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	pi, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(fi, pi), nil
+}
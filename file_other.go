@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package apidoc
+
+import "os"
+
+// advisory lock is not supported, writes are still atomic
+
+func flock(file *os.File) error {
+	return nil
+}
+
+func funlock(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,74 @@
+package apidoc
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filePath := filepath.Join(dir, "apidoc.json")
+	if err := writeFileAtomic(filePath, func(w io.Writer) error {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "[]" {
+		t.Fatal(string(b))
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatal("temp file is left")
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filePath := filepath.Join(dir, "apidoc.json")
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked := make(chan struct{})
+	go func() {
+		unlock2, err := lockFile(filePath)
+		if err != nil {
+			t.Error(err)
+		} else {
+			unlock2()
+		}
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("lock must wait for unlock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+	<-locked
+	if _, err := os.Stat(filePath + ".lock"); !os.IsNotExist(err) {
+		t.Fatal("lock file is left", err)
+	}
+}
@@ -0,0 +1,78 @@
+package apidoc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Group has apis of a tag or resource
+type Group struct {
+	Name string
+	APIs []API
+}
+
+// GroupName return first tag, or first path segment except version like users of /v1/users
+func (a API) GroupName() string {
+	if len(a.Tags) > 0 {
+		return a.Tags[0]
+	}
+	for _, segment := range strings.Split(a.RequestPath, "/") {
+		if segment == "" || segment == a.Version {
+			continue
+		}
+		return segment
+	}
+	return "/"
+}
+
+var anchorPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Anchor return stable id of api for deep links, e.g. get-users-200
+// It may collide like those of /users/list and /users-list, documents number them, see apiAnchor in TemplateFuncs.
+func (a API) Anchor() string {
+	return anchor(strings.Join([]string{a.Version, a.RequestMethod, a.RequestPath, strconv.Itoa(a.ResponseStatusCode)}, " "))
+}
+
+// anchorKey identify api in project like API.equal
+type anchorKey struct {
+	endpoint
+	statusCode int
+}
+
+// anchors return unique anchor of each api, colliding anchors like those of /users/list and /users-list are numbered like get-users-list-200-2 in order of apis
+func (p *Project) anchors() map[anchorKey]string {
+	anchors := map[anchorKey]string{}
+	used := map[string]bool{}
+	for _, api := range p.APIs {
+		base := api.Anchor()
+		a := base
+		for n := 2; used[a]; n++ {
+			a = base + "-" + strconv.Itoa(n)
+		}
+		used[a] = true
+		anchors[api.anchorKey()] = a
+	}
+	return anchors
+}
+
+func (a API) anchorKey() anchorKey {
+	return anchorKey{endpoint{version: a.Version, method: a.RequestMethod, path: a.RequestPath}, a.ResponseStatusCode}
+}
+
+// Groups group apis by GroupName in order of appearance
+func (p *Project) Groups() []Group {
+	var groups []Group
+	index := map[string]int{}
+	for _, api := range p.APIs {
+		name := api.GroupName()
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, Group{Name: name})
+		}
+		groups[i].APIs = append(groups[i].APIs, api)
+	}
+	return groups
+}
@@ -0,0 +1,86 @@
+package apidoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	users := newTestAPI()
+	user := newTestAPI()
+	user.RequestPath = "/v1/users/1"
+	user.Version = "v1"
+	tagged := newTestAPI()
+	tagged.RequestPath = "/login"
+	tagged.Tags = []string{"auth"}
+	root := newTestAPI()
+	root.RequestPath = "/"
+
+	p := Project{APIs: []API{users, tagged, user, root}}
+	groups := p.Groups()
+	if len(groups) != 3 {
+		t.Fatalf("%+v", groups)
+	}
+	if groups[0].Name != "users" || len(groups[0].APIs) != 2 {
+		t.Fatalf("%+v", groups[0])
+	}
+	if groups[1].Name != "auth" || groups[2].Name != "/" {
+		t.Fatalf("%+v", groups)
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	api := newTestAPI()
+	api.RequestPath = "/users/{id}"
+	if got := api.Anchor(); got != "get-users-id-200" {
+		t.Fatal(got)
+	}
+	api.Version = "v1"
+	if got := api.Anchor(); got != "v1-get-users-id-200" {
+		t.Fatal(got)
+	}
+}
+
+func TestWriteHTMLCollidingAnchors(t *testing.T) {
+	slash := newTestAPI()
+	slash.RequestPath = "/users/list"
+	hyphen := newTestAPI()
+	hyphen.RequestPath = "/users-list"
+	p := Project{DocumentTitle: "apidoc-test", APIs: []API{slash, hyphen}}
+	var buf bytes.Buffer
+	if err := p.WriteHTML(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`id="get-users-list-200"`, `id="get-users-list-200-2"`, `href="#get-users-list-200-2"`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Fatalf("%s is not rendered", s)
+		}
+	}
+	if n := strings.Count(buf.String(), `id="get-users-list-200"`); n != 1 {
+		t.Fatal("id must be unique", n)
+	}
+}
+
+func TestWriteHTMLGroups(t *testing.T) {
+	tagged := newTestAPI()
+	tagged.RequestPath = "/login"
+	tagged.RequestMethod = "POST"
+	tagged.Tags = []string{"auth"}
+	p := Project{DocumentTitle: "apidoc-test", APIs: []API{newTestAPI(), tagged}}
+	var buf bytes.Buffer
+	if err := p.WriteHTML(&buf, "default.tpl.html"); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<summary>auth</summary>`,
+		`id="post-login-200"`,
+		`href="#get-users-200"`,
+		`style="background: #27ae60"`,
+		`id="search"`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(s)) {
+			t.Fatalf("%s is not rendered", s)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package apidoc
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+)
+
+// reloads notify documents served by Handler to reload
+var reloads = &broadcaster{subscribers: map[chan struct{}]bool{}}
+
+type broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]bool
+}
+
+// subscribe return channel notified on reload, call returned func to unsubscribe
+func (b *broadcaster) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.subscribers[ch] = true
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}
+}
+
+// notify notify subscribers without blocking, pending notification is not duplicated
+func (b *broadcaster) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// reloadScript reload html document on events sent to <document path>/events
+const reloadScript = `<script type="text/javascript">
+new EventSource(location.pathname.replace(/\/?$/, '/events')).onmessage = function () {
+    location.reload();
+};
+</script>
+`
+
+// DocsHandler serve documents of project recorded by Gen from memory
+// Add its mount path to Project.ExcludePaths so that requests to it are not recorded.
+type DocsHandler struct {
+	// TemplatePath is used to render html, TemplatePath of project or default template if empty
+	TemplatePath string
+	// Username and Password guard documents with basic auth if either is set
+	Username string
+	Password string
+}
+
+type docsExport struct {
+	format      Format
+	contentType string
+}
+
+// docsExports are served by DocsHandler by file name
+var docsExports = map[string]docsExport{
+	"apidoc.json":             {FormatJSON, "application/json; charset=utf-8"},
+	"openapi.json":            {FormatOpenAPI, "application/json; charset=utf-8"},
+	"apidoc.md":               {FormatMarkdown, "text/markdown; charset=utf-8"},
+	"postman_collection.json": {FormatPostman, "application/json; charset=utf-8"},
+	"apidoc.har":              {FormatHAR, "application/json; charset=utf-8"},
+}
+
+// Handler return handler serving documents of project recorded by Gen, by last path segment of request
+//
+//	events                   Server-Sent Events sending "reload" when Gen records apis
+//	apidoc.json              apis json
+//	openapi.json             OpenAPI 3.0 json, ?version=v1 exports a version like other exports
+//	apidoc.md                markdown
+//	postman_collection.json  Postman Collection v2.1
+//	apidoc.har               HAR 1.2
+//	otherwise                html document, reloaded by events
+func Handler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+func (h *DocsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="apidoc"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	name := path.Base(r.URL.Path)
+	if name == "events" {
+		h.serveEvents(w, r)
+		return
+	}
+	if e, ok := docsExports[name]; ok {
+		h.serveOutput(w, Output{Format: e.format, Version: r.URL.Query().Get("version")}, e.contentType)
+		return
+	}
+	h.serveOutput(w, Output{Format: FormatHTML, TemplatePath: h.TemplatePath}, "text/html; charset=utf-8")
+}
+
+func (h *DocsHandler) authorized(r *http.Request) bool {
+	if h.Username == "" && h.Password == "" {
+		return true
+	}
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	// compare both to take same time whichever is wrong
+	userOK := subtle.ConstantTimeCompare([]byte(username), []byte(h.Username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(password), []byte(h.Password)) == 1
+	return userOK && passOK
+}
+
+func (h *DocsHandler) serveOutput(w http.ResponseWriter, o Output, contentType string) {
+	var b bytes.Buffer
+	sp := snapshot()
+	if o.Version != "" {
+		sp = sp.ForVersion(o.Version)
+	}
+	if err := sp.Render(&b, o); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := b.Bytes()
+	if o.Format == FormatHTML {
+		out = injectBeforeBodyEnd(out, reloadScript)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(out)
+}
+
+func (h *DocsHandler) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	ch, unsubscribe := reloads.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// injectBeforeBodyEnd insert s before last </body>, or append it if not found
+func injectBeforeBodyEnd(html []byte, s string) []byte {
+	i := bytes.LastIndex(html, []byte("</body>"))
+	if i < 0 {
+		return append(html, s...)
+	}
+	out := make([]byte, 0, len(html)+len(s))
+	out = append(out, html[:i]...)
+	out = append(out, s...)
+	return append(out, html[i:]...)
+}
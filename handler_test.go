@@ -0,0 +1,190 @@
+package apidoc
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	if err := Init(Project{DocumentTitle: "apidoc-test", Store: &MemoryStore{}, DisableOutputs: true}); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(Handler())
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatal(ct)
+	}
+
+	if err := Gen(newTestAPI()); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != "data: reload\n" {
+		t.Fatal(line)
+	}
+
+	res, err = http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(b)
+	if !strings.Contains(html, `id="get-users-200"`) {
+		t.Fatal("recorded api is not rendered")
+	}
+	if !strings.Contains(html, "new EventSource") || strings.Index(html, "new EventSource") > strings.LastIndex(html, "</body>") {
+		t.Fatal("reload script is not injected")
+	}
+
+	res, err = http.Get(ts.URL + "/apidoc.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	apis, err := ReadAPIs(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 1 {
+		t.Fatalf("%+v", apis)
+	}
+}
+
+func TestGenConcurrently(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	if err := Init(Project{Store: &MemoryStore{}, DisableOutputs: true}); err != nil {
+		t.Fatal(err)
+	}
+	h := Handler()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			api := newTestAPI()
+			api.RequestPath = "/users/" + strconv.Itoa(i)
+			if err := Gen(api); err != nil {
+				t.Error(err)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/apidoc.json", nil))
+		}(i)
+	}
+	wg.Wait()
+	if n := len(snapshot().APIs); n != 10 {
+		t.Fatalf("%d apis are recorded", n)
+	}
+}
+
+func TestHandlerExports(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	if err := Init(Project{DocumentTitle: "apidoc-test", Store: &MemoryStore{}, DisableOutputs: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Gen(newTestAPI()); err != nil {
+		t.Fatal(err)
+	}
+	h := Handler()
+	for name, want := range map[string]string{
+		"/_apidoc/openapi.json":            `"openapi": "3.0.3"`,
+		"/_apidoc/apidoc.md":               "## GET /users",
+		"/_apidoc/postman_collection.json": "schema.getpostman.com",
+		"/_apidoc/apidoc.har":              `"log"`,
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", name, nil))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), want) {
+			t.Fatalf("%s: %d %s", name, w.Code, w.Body.String())
+		}
+	}
+
+	v2 := newTestAPI()
+	v2.Version = "v2"
+	if err := Gen(v2); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/_apidoc/openapi.json?version=v2", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"version": "v2"`) {
+		t.Fatalf("%d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandlerBasicAuth(t *testing.T) {
+	h := Handler()
+	h.Username, h.Password = "admin", "secret"
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("%d %v", w.Code, w.Header())
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("admin", "wrong")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatal(w.Code)
+	}
+
+	r = httptest.NewRequest("GET", "/apidoc.json", nil)
+	r.SetBasicAuth("admin", "secret")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatal(w.Code)
+	}
+}
+
+func TestGenExcludePaths(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	if err := Init(Project{Store: &MemoryStore{}, DisableOutputs: true, ExcludePaths: []string{"/_apidoc/"}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/_apidoc", "/_apidoc/openapi.json", "/_apidocs"} {
+		api := newTestAPI()
+		api.RequestPath = path
+		if err := Gen(api); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apis := snapshot().APIs
+	if len(apis) != 1 || apis[0].RequestPath != "/_apidocs" {
+		t.Fatalf("%+v", apis)
+	}
+	if !IsExcluded("/_apidoc/events") || IsExcluded("/users") {
+		t.Fatal("IsExcluded is wrong")
+	}
+}
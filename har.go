@@ -0,0 +1,303 @@
+package apidoc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const harVersion = "1.2"
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Params   []harNameValue `json:"params,omitempty"`
+	Text     string         `json:"text,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// WriteHAR write apis as HAR 1.2 to w
+func (p *Project) WriteHAR(w io.Writer) error {
+	h := harFile{
+		Log: harLog{
+			Version: harVersion,
+			Creator: harCreator{Name: "apidoc", Version: harVersion},
+			Entries: []harEntry{},
+		},
+	}
+	for _, api := range p.APIs {
+		h.Log.Entries = append(h.Log.Entries, newHAREntry(api, p.BaseURL))
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	out, err := PrettyPrint(b)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func newHAREntry(api API, baseURL string) harEntry {
+	req := harRequest{
+		Method:      api.RequestMethod,
+		URL:         api.requestURL(baseURL),
+		HTTPVersion: harHTTPVersion(api.RequestProto),
+		Cookies:     []harNameValue{},
+		Headers:     harNameValues(api.RequestHeaders),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if api.RequestContentLength > 0 {
+		req.BodySize = int(api.RequestContentLength)
+	}
+	for _, key := range sortedKeys(api.RequestURLParams) {
+		req.QueryString = append(req.QueryString, harNameValue{Name: key, Value: unescapeQuery(api.RequestURLParams[key])})
+	}
+	switch {
+	case len(api.RequestPostForms) > 0:
+		req.PostData = &harPostData{MimeType: api.requestContentType()}
+		values := []string{}
+		for _, key := range sortedKeys(api.RequestPostForms) {
+			req.PostData.Params = append(req.PostData.Params, harNameValue{Name: key, Value: unescapeQuery(api.RequestPostForms[key])})
+			values = append(values, key+"="+api.RequestPostForms[key])
+		}
+		req.PostData.Text = strings.Join(values, "&")
+	case api.RequestBody != "":
+		req.PostData = &harPostData{MimeType: api.requestContentType(), Text: api.RequestBody}
+	}
+
+	res := harResponse{
+		Status:      api.ResponseStatusCode,
+		StatusText:  http.StatusText(api.ResponseStatusCode),
+		HTTPVersion: harHTTPVersion(api.RequestProto),
+		Cookies:     []harNameValue{},
+		Headers:     harNameValues(api.ResponseHeaders),
+		Content: harContent{
+			Size:     len(api.ResponseBody),
+			MimeType: api.responseContentType(),
+			Text:     api.ResponseBody,
+		},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	ms := durationMilliseconds(api.Duration)
+	return harEntry{
+		StartedDateTime: api.RequestStartedAt,
+		Time:            ms,
+		Request:         req,
+		Response:        res,
+		Timings:         harTimings{Wait: ms},
+	}
+}
+
+func harHTTPVersion(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func harNameValues(m map[string]string) []harNameValue {
+	values := []harNameValue{}
+	for _, key := range sortedKeys(m) {
+		values = append(values, harNameValue{Name: key, Value: headerValue(m[key])})
+	}
+	return values
+}
+
+func unescapeQuery(s string) string {
+	v, err := url.QueryUnescape(s)
+	if err != nil {
+		return s
+	}
+	return v
+}
+
+// ReadHAR read HAR file from r and append entries to apis
+func (p *Project) ReadHAR(r io.Reader) error {
+	apis, err := readHAR(r)
+	if err != nil {
+		return err
+	}
+	for _, api := range apis {
+		p.appendAPI(api)
+	}
+	return nil
+}
+
+func readHAR(r io.Reader) ([]API, error) {
+	var h harFile
+	if err := json.NewDecoder(r).Decode(&h); err != nil {
+		return nil, err
+	}
+	apis := make([]API, 0, len(h.Log.Entries))
+	for _, entry := range h.Log.Entries {
+		api, err := newAPIFromHAREntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		apis = append(apis, api)
+	}
+	return apis, nil
+}
+
+func newAPIFromHAREntry(entry harEntry) (API, error) {
+	api := NewAPI()
+	u, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return api, err
+	}
+	api.RequestMethod = entry.Request.Method
+	api.RequestPath = u.EscapedPath()
+	if api.RequestPath == "" {
+		api.RequestPath = "/"
+	}
+	api.RequestScheme = u.Scheme
+	api.RequestHost = u.Host
+	api.RequestProto = entry.Request.HTTPVersion
+	api.RequestRawQuery = u.RawQuery
+	api.RequestLine = api.RequestMethod + " " + u.RequestURI() + " " + api.RequestProto
+	if entry.Request.BodySize > 0 {
+		api.RequestContentLength = int64(entry.Request.BodySize)
+	}
+	api.RequestStartedAt = entry.StartedDateTime
+	api.Duration = time.Duration(entry.Time * float64(time.Millisecond))
+	for key, values := range u.Query() {
+		if key != "" && len(values) > 0 {
+			api.RequestURLParams[key] = url.QueryEscape(values[0])
+		}
+	}
+	for _, h := range entry.Request.Headers {
+		// skip HTTP/2 pseudo headers like :authority
+		if h.Name == "" || strings.HasPrefix(h.Name, ":") {
+			continue
+		}
+		api.RequestHeaders[http.CanonicalHeaderKey(h.Name)] = h.Value
+	}
+	if pd := entry.Request.PostData; pd != nil {
+		switch {
+		case len(pd.Params) > 0:
+			for _, param := range pd.Params {
+				api.RequestPostForms[param.Name] = url.QueryEscape(param.Value)
+			}
+		case strings.Contains(pd.MimeType, "application/x-www-form-urlencoded"):
+			values, err := url.ParseQuery(pd.Text)
+			if err != nil {
+				return api, err
+			}
+			for key := range values {
+				api.RequestPostForms[key] = url.QueryEscape(values.Get(key))
+			}
+		default:
+			api.RequestBody = prettyPrintIfJSON(pd.Text)
+		}
+		if _, ok := api.RequestHeaders["Content-Type"]; !ok && pd.MimeType != "" {
+			api.RequestHeaders["Content-Type"] = pd.MimeType
+		}
+	}
+
+	api.ResponseStatusCode = entry.Response.Status
+	for _, h := range entry.Response.Headers {
+		if h.Name == "" || strings.HasPrefix(h.Name, ":") {
+			continue
+		}
+		api.ResponseHeaders[http.CanonicalHeaderKey(h.Name)] = h.Value
+	}
+	text := entry.Response.Content.Text
+	if entry.Response.Content.Encoding == "base64" {
+		b, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return api, err
+		}
+		text = string(b)
+	}
+	api.ResponseBody = prettyPrintIfJSON(text)
+	return api, nil
+}
+
+// ImportHAR read HAR file and generate api document, entries are recorded like Gen
+func ImportHAR(r io.Reader) error {
+	apis, err := readHAR(r)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return gen(apis...)
+}
@@ -0,0 +1,117 @@
+package apidoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteAndReadHAR(t *testing.T) {
+	a1 := newTestAPI()
+	a2 := newTestAPI()
+	a2.RequestMethod = "POST"
+	a2.RequestHeaders["Content-Type"] = "application/x-www-form-urlencoded"
+	a2.RequestPostForms["name"] = "goto+katsuya"
+	a2.ResponseStatusCode = 201
+	p := Project{APIs: []API{a1, a2}}
+	var b bytes.Buffer
+	if err := p.WriteHAR(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	p2 := Project{APIs: []API{}}
+	if err := p2.ReadHAR(&b); err != nil {
+		t.Fatal(err)
+	}
+	if len(p2.APIs) != 2 {
+		t.Fatal("API len is not 2")
+	}
+	if p2.APIs[0].RequestURLParams["limit"] != "30" {
+		t.Fatal("limit is not equal")
+	}
+	if p2.APIs[0].ResponseBody != a1.ResponseBody {
+		t.Fatal("ResponseBody is not equal")
+	}
+	if p2.APIs[1].RequestPostForms["name"] != "goto+katsuya" {
+		t.Fatal("name is not equal")
+	}
+	if p2.APIs[1].ResponseStatusCode != 201 {
+		t.Fatal("ResponseStatusCode is not equal")
+	}
+}
+
+func TestReadHARDevtools(t *testing.T) {
+	const har = `{"log":{"version":"1.2","creator":{"name":"WebInspector","version":"537.36"},"entries":[{
+		"startedDateTime":"2017-01-01T00:00:00.000Z","time":10,
+		"request":{"method":"PUT","url":"https://example.com/users/1?q=a%20b","httpVersion":"http/2.0",
+			"headers":[{"name":":authority","value":"example.com"},{"name":"content-type","value":"application/json"}],
+			"queryString":[],"cookies":[],"headersSize":-1,"bodySize":15,
+			"postData":{"mimeType":"application/json","text":"{\"name\":\"test\"}"}},
+		"response":{"status":200,"statusText":"OK","httpVersion":"http/2.0","headers":[],"cookies":[],
+			"content":{"size":2,"mimeType":"application/json","text":"e30=","encoding":"base64"},
+			"redirectURL":"","headersSize":-1,"bodySize":-1},
+		"cache":{},"timings":{"send":0,"wait":10,"receive":0}}]}}`
+	p := Project{APIs: []API{}}
+	if err := p.ReadHAR(strings.NewReader(har)); err != nil {
+		t.Fatal(err)
+	}
+	api := p.APIs[0]
+	if api.RequestPath != "/users/1" {
+		t.Fatal(api.RequestPath)
+	}
+	if _, ok := api.RequestHeaders[":authority"]; ok {
+		t.Fatal("pseudo header must be skipped")
+	}
+	if api.RequestHeaders["Content-Type"] != "application/json" {
+		t.Fatal("Content-Type is not equal")
+	}
+	if api.RequestURLParams["q"] != "a+b" {
+		t.Fatal(api.RequestURLParams["q"])
+	}
+	if api.RequestBody != "{\n  \"name\": \"test\"\n}" {
+		t.Fatal(api.RequestBody)
+	}
+	if api.ResponseBody != "{}" {
+		t.Fatal(api.ResponseBody)
+	}
+}
+
+func TestImportHAR(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	a1 := newTestAPI()
+	a1.ResponseBody = `{"id": "tok_1a2b"}`
+	a2 := newTestAPI()
+	a2.RequestPath = "/_apidoc/openapi.json"
+	var b bytes.Buffer
+	if err := (&Project{APIs: []API{a1, a2}}).WriteHAR(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &MemoryStore{}
+	p = Project{
+		Store:        store,
+		Outputs:      []Output{},
+		ExcludePaths: []string{"/_apidoc"},
+		Normalizers:  []Normalizer{NormalizeRegexp(`tok_[a-z0-9]+`, "tok_xxx")},
+	}
+	reloaded, unsubscribe := reloads.subscribe()
+	defer unsubscribe()
+	if err := ImportHAR(&b); err != nil {
+		t.Fatal(err)
+	}
+	apis, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 1 || apis[0].RequestPath != "/users" || !strings.Contains(apis[0].ResponseBody, `"tok_xxx"`) {
+		t.Fatalf("imported apis must be excluded and normalized like Gen %+v", apis)
+	}
+	select {
+	case <-reloaded:
+	default:
+		t.Fatal("documents must be reloaded")
+	}
+}
@@ -3,6 +3,7 @@ package apidoc
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 )
 
 // PrettyPrint print rich json
@@ -13,3 +14,68 @@ func PrettyPrint(in []byte) ([]byte, error) {
 	}
 	return out.Bytes(), nil
 }
+
+func isJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") && !strings.HasPrefix(s, "[") {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// jsonTypeOf return json type name of decoded value
+func jsonTypeOf(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64, json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func prettyPrintIfJSON(s string) string {
+	if !isJSON(s) {
+		return s
+	}
+	out, err := PrettyPrint([]byte(s))
+	if err != nil {
+		return s
+	}
+	return string(out)
+}
+
+// jsonShape flatten json into path and type, e.g. "$.users[].id" is "number"
+// Elements of array are merged into "[]".
+func jsonShape(s string) (map[string]string, bool) {
+	if !isJSON(s) {
+		return nil, false
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, false
+	}
+	shape := map[string]string{}
+	walkJSONShape(shape, "$", v)
+	return shape, true
+}
+
+func walkJSONShape(shape map[string]string, path string, v interface{}) {
+	shape[path] = jsonTypeOf(v)
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			walkJSONShape(shape, path+"."+key, value)
+		}
+	case []interface{}:
+		for _, value := range t {
+			walkJSONShape(shape, path+"[]", value)
+		}
+	}
+}
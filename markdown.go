@@ -0,0 +1,75 @@
+package apidoc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown write markdown document to w
+func (p *Project) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if p.DocumentTitle != "" {
+		fmt.Fprintf(bw, "# %s\n\n", p.DocumentTitle)
+	}
+	for _, api := range p.APIs {
+		writeMarkdownAPI(bw, api, p.BaseURL)
+	}
+	return bw.Flush()
+}
+
+func writeMarkdownAPI(w io.Writer, api API, baseURL string) {
+	fmt.Fprintf(w, "## %s %s\n\n", api.RequestMethod, api.RequestPath)
+	if api.RequestLine != "" {
+		fmt.Fprintf(w, "```http\n%s\n```\n\n", api.RequestLine)
+	}
+	writeMarkdownTable(w, "Request Headers", api.RequestHeaders)
+	writeMarkdownTable(w, "Post Form", api.RequestPostForms)
+	writeMarkdownTable(w, "URL Params", api.RequestURLParams)
+	writeMarkdownCode(w, "Request Body", api.RequestBody)
+	fmt.Fprintf(w, "### curl\n\n```sh\n%s\n```\n\n", api.CurlCommand(baseURL))
+	if api.ResponseStatusCode != 0 {
+		fmt.Fprintf(w, "### Response Code\n\n%d\n\n", api.ResponseStatusCode)
+	}
+	if api.Duration != 0 {
+		fmt.Fprintf(w, "### Duration\n\n%s\n\n", api.Duration)
+	}
+	writeMarkdownTable(w, "Response Headers", api.ResponseHeaders)
+	writeMarkdownCode(w, "Response Body", api.ResponseBody)
+	for i, example := range api.Examples {
+		fmt.Fprintf(w, "### Example %d\n\n", i+2)
+		writeMarkdownTable(w, "Request Headers", example.RequestHeaders)
+		writeMarkdownTable(w, "Post Form", example.RequestPostForms)
+		writeMarkdownTable(w, "URL Params", example.RequestURLParams)
+		writeMarkdownCode(w, "Request Body", example.RequestBody)
+		writeMarkdownTable(w, "Response Headers", example.ResponseHeaders)
+		writeMarkdownCode(w, "Response Body", example.ResponseBody)
+	}
+}
+
+func writeMarkdownTable(w io.Writer, title string, m map[string]string) {
+	if len(m) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "### %s\n\n| Key | Value |\n| --- | --- |\n", title)
+	for _, key := range sortedKeys(m) {
+		fmt.Fprintf(w, "| %s | %s |\n", escapeMarkdownCell(key), escapeMarkdownCell(headerValue(m[key])))
+	}
+	fmt.Fprint(w, "\n")
+}
+
+func writeMarkdownCode(w io.Writer, title, body string) {
+	if body == "" {
+		return
+	}
+	lang := ""
+	if isJSON(body) {
+		lang = "json"
+	}
+	fmt.Fprintf(w, "### %s\n\n```%s\n%s\n```\n\n", title, lang, strings.TrimRight(body, "\n"))
+}
+
+func escapeMarkdownCell(s string) string {
+	return strings.Replace(s, "|", `\|`, -1)
+}
@@ -0,0 +1,46 @@
+package apidoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	p := Project{
+		DocumentTitle: "apidoc-test",
+		APIs:          []API{newTestAPI()},
+	}
+	var b bytes.Buffer
+	if err := p.WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# apidoc-test",
+		"## GET /users",
+		"| limit | 30 |",
+		"| Content-Type | application/json; charset=utf-8 |",
+		"```json",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("%q is not contained in\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteMarkdownExamples(t *testing.T) {
+	api := newTestAPI()
+	example := newTestAPI()
+	example.ResponseBody = `{"example": 2}`
+	api.Examples = []API{example}
+	p := Project{APIs: []API{api}}
+	var b bytes.Buffer
+	if err := p.WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if !strings.Contains(out, "### Example 2") || !strings.Contains(out, `{"example": 2}`) {
+		t.Fatal(out)
+	}
+}
@@ -0,0 +1,149 @@
+package apidoc
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// MergePolicy decide how to merge apis which have same method, path and status code
+type MergePolicy int
+
+const (
+	// MergeLatestWins keep api recorded later, or given later if not recorded time
+	MergeLatestWins MergePolicy = iota
+	// MergeKeepBoth keep later api in API.Examples of former api
+	MergeKeepBoth
+	// MergeFail return *MergeConflictError
+	MergeFail
+)
+
+// ParseMergePolicy parse latest, keep-both or fail
+func ParseMergePolicy(s string) (MergePolicy, error) {
+	switch s {
+	case "latest":
+		return MergeLatestWins, nil
+	case "keep-both":
+		return MergeKeepBoth, nil
+	case "fail":
+		return MergeFail, nil
+	}
+	return 0, fmt.Errorf("apidoc: unknown merge policy %q", s)
+}
+
+// MergeConflictError is returned by MergeWithPolicy with MergeFail
+type MergeConflictError struct {
+	Version    string
+	Method     string
+	Path       string
+	StatusCode int
+}
+
+func (e *MergeConflictError) Error() string {
+	if e.Version != "" {
+		return fmt.Sprintf("apidoc: conflict %s %s %s %d", e.Version, e.Method, e.Path, e.StatusCode)
+	}
+	return fmt.Sprintf("apidoc: conflict %s %s %d", e.Method, e.Path, e.StatusCode)
+}
+
+// Merge merge apis, later api replaces api which has same method, path and status code
+func Merge(apis ...[]API) []API {
+	merged, _ := MergeWithPolicy(MergeLatestWins, apis...)
+	return merged
+}
+
+// MergeWithPolicy merge apis, same apis are merged by policy
+// Identical exchanges are never regarded as conflict.
+func MergeWithPolicy(policy MergePolicy, apis ...[]API) ([]API, error) {
+	merged := []API{}
+	for _, list := range apis {
+		for _, api := range list {
+			i := indexOfAPI(merged, api)
+			switch {
+			case i < 0:
+				merged = append(merged, api)
+			case sameExchange(merged[i], api):
+				if api.RequestStartedAt.After(merged[i].RequestStartedAt) {
+					merged[i] = api
+				}
+			case policy == MergeLatestWins:
+				if !merged[i].RequestStartedAt.IsZero() && !api.RequestStartedAt.IsZero() && api.RequestStartedAt.Before(merged[i].RequestStartedAt) {
+					continue
+				}
+				merged[i] = api
+			case policy == MergeKeepBoth:
+				merged[i] = addExamples(merged[i], api)
+			default:
+				return nil, &MergeConflictError{
+					Version:    api.Version,
+					Method:     api.RequestMethod,
+					Path:       api.RequestPath,
+					StatusCode: api.ResponseStatusCode,
+				}
+			}
+		}
+	}
+	return merged, nil
+}
+
+func indexOfAPI(apis []API, api API) int {
+	for i, a := range apis {
+		if a.equal(api) {
+			return i
+		}
+	}
+	return -1
+}
+
+// addExamples add api and its examples to Examples of base, unless base already has the exchange
+func addExamples(base, api API) API {
+	examples := append([]API{api}, api.Examples...)
+	examples[0].Examples = nil
+	for _, example := range examples {
+		if containsExchange(base, example) {
+			continue
+		}
+		base.Examples = append(base.Examples, example)
+	}
+	return base
+}
+
+func containsExchange(base, api API) bool {
+	primary := base
+	primary.Examples = nil
+	if sameExchange(primary, api) {
+		return true
+	}
+	for _, example := range base.Examples {
+		if sameExchange(example, api) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameExchange compare apis without timing
+func sameExchange(a1, a2 API) bool {
+	a1.RequestStartedAt, a2.RequestStartedAt = time.Time{}, time.Time{}
+	a1.Duration, a2.Duration = 0, 0
+	return reflect.DeepEqual(a1, a2)
+}
+
+// MergeFiles merge document json files into apis by policy, and sort them by SortBy
+func (p *Project) MergeFiles(policy MergePolicy, filePaths ...string) error {
+	lists := [][]API{p.APIs}
+	for _, filePath := range filePaths {
+		apis, err := LoadAPIs(filePath)
+		if err != nil {
+			return err
+		}
+		lists = append(lists, apis)
+	}
+	merged, err := MergeWithPolicy(policy, lists...)
+	if err != nil {
+		return err
+	}
+	p.APIs = merged
+	SortAPIs(p.APIs, p.SortBy)
+	return nil
+}
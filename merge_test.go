@@ -0,0 +1,97 @@
+package apidoc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	a1 := newTestAPI()
+	a2 := newTestAPI()
+	a2.ResponseBody = "{}"
+	a3 := newTestAPI()
+	a3.RequestPath = "/items"
+	merged := Merge([]API{a1}, []API{a2, a3})
+	if len(merged) != 2 {
+		t.Fatal("API len is not 2")
+	}
+	if merged[0].ResponseBody != "{}" {
+		t.Fatal("later api must win")
+	}
+}
+
+func TestMergeWithPolicy(t *testing.T) {
+	now := time.Now()
+	a1 := newTestAPI()
+	a1.RequestStartedAt = now
+	a2 := newTestAPI()
+	a2.ResponseBody = "{}"
+	a2.RequestStartedAt = now.Add(-time.Second)
+	same := newTestAPI()
+	same.RequestStartedAt = now.Add(time.Second)
+
+	merged, err := MergeWithPolicy(MergeLatestWins, []API{a1}, []API{a2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 1 || merged[0].ResponseBody != a1.ResponseBody {
+		t.Fatal("recorded later api must win")
+	}
+
+	merged, err = MergeWithPolicy(MergeKeepBoth, []API{a1}, []API{a2, same})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 1 || len(merged[0].Examples) != 1 || merged[0].Examples[0].ResponseBody != "{}" {
+		t.Fatalf("other exchange must be kept as example %+v", merged)
+	}
+	if errs := Validate(merged); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	again, err := MergeWithPolicy(MergeKeepBoth, merged, []API{a2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 1 || len(again[0].Examples) != 1 {
+		t.Fatalf("kept example must not be added again %+v", again)
+	}
+
+	if _, err := MergeWithPolicy(MergeFail, []API{a1}, []API{same}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MergeWithPolicy(MergeFail, []API{a1}, []API{a2}); err == nil {
+		t.Fatal("conflict must be error")
+	} else if _, ok := err.(*MergeConflictError); !ok {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	a1 := newTestAPI()
+	a1.RequestPath = "/items"
+	filePath := filepath.Join(dir, "items.json")
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Project{APIs: []API{a1}}).WriteJSON(file); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	p := Project{APIs: []API{newTestAPI()}}
+	if err := p.MergeFiles(MergeFail, filePath); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.APIs) != 2 {
+		t.Fatal("API len is not 2")
+	}
+}
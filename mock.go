@@ -0,0 +1,159 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// mockSkippedHeaders are written by http server for the body mock writes, recorded values may not match it
+var mockSkippedHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+}
+
+// MockHandler serve recorded responses as fake api
+type MockHandler struct {
+	APIs []API
+	// MatchQuery require same url params
+	MatchQuery bool
+	// MatchBody require same request body or post forms
+	MatchBody bool
+	// Versioner select apis of request version, e.g. VersionByHeader("Accept-Version")
+	Versioner Versioner
+}
+
+// NewMockHandler new mock handler instance
+func NewMockHandler(apis []API) *MockHandler {
+	return &MockHandler{APIs: apis}
+}
+
+// normalizePath clean path and trim trailing slash
+func normalizePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	p = path.Clean("/" + p)
+	if p != "/" {
+		p = strings.TrimRight(p, "/")
+	}
+	return p
+}
+
+func (h *MockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reqPath := normalizePath(r.URL.EscapedPath())
+	version, versioned := h.requestVersion(r)
+	var found *API
+	pathFound, methodFound := false, false
+	for i, api := range h.APIs {
+		if normalizePath(api.RequestPath) != reqPath {
+			continue
+		}
+		pathFound = true
+		if api.RequestMethod != r.Method {
+			continue
+		}
+		methodFound = true
+		if versioned && api.Version != version {
+			continue
+		}
+		if h.MatchQuery && !matchQuery(api, r.URL.Query()) {
+			continue
+		}
+		if h.MatchBody && !matchBody(api, body) {
+			continue
+		}
+		// prefer success response
+		if found == nil || (!isSuccessStatus(found.ResponseStatusCode) && isSuccessStatus(api.ResponseStatusCode)) {
+			found = &h.APIs[i]
+		}
+	}
+	if found == nil {
+		status := http.StatusNotFound
+		if pathFound && !methodFound {
+			status = http.StatusMethodNotAllowed
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("apidoc: no recorded api for %s %s", r.Method, r.URL.Path),
+		})
+		return
+	}
+
+	for _, key := range sortedKeys(found.ResponseHeaders) {
+		if mockSkippedHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		w.Header().Set(key, headerValue(found.ResponseHeaders[key]))
+	}
+	status := found.ResponseStatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(found.ResponseBody))
+}
+
+// requestVersion return version of request by Versioner, false if Versioner is not set
+func (h *MockHandler) requestVersion(r *http.Request) (string, bool) {
+	if h.Versioner == nil {
+		return "", false
+	}
+	api := NewAPI()
+	api.RequestMethod = r.Method
+	api.RequestPath = r.URL.EscapedPath()
+	api.ReadRequestHeader(r.Header)
+	return h.Versioner(api), true
+}
+
+func isSuccessStatus(code int) bool {
+	return code >= 200 && code < 300
+}
+
+func matchQuery(api API, query url.Values) bool {
+	if len(api.RequestURLParams) != len(query) {
+		return false
+	}
+	for key, value := range api.RequestURLParams {
+		if query.Get(key) != unescapeQuery(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchBody(api API, body []byte) bool {
+	if len(api.RequestPostForms) > 0 {
+		values, err := url.ParseQuery(string(body))
+		if err != nil || len(values) != len(api.RequestPostForms) {
+			return false
+		}
+		for key, value := range api.RequestPostForms {
+			if values.Get(key) != unescapeQuery(value) {
+				return false
+			}
+		}
+		return true
+	}
+	if isJSON(api.RequestBody) {
+		var expected, actual interface{}
+		if json.Unmarshal([]byte(api.RequestBody), &expected) != nil || json.Unmarshal(body, &actual) != nil {
+			return false
+		}
+		return reflect.DeepEqual(expected, actual)
+	}
+	return api.RequestBody == string(body)
+}
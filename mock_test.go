@@ -0,0 +1,116 @@
+package apidoc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"":             "/",
+		"/":            "/",
+		"/users/":      "/users",
+		"users":        "/users",
+		"/users//1/..": "/users",
+	} {
+		if got := normalizePath(in); got != want {
+			t.Fatalf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMockHandler(t *testing.T) {
+	a1 := newTestAPI()
+	a1.ResponseStatusCode = 400
+	a1.ResponseBody = `{"error": "bad request"}`
+	a2 := newTestAPI()
+	a3 := newTestAPI()
+	a3.RequestMethod = "PUT"
+	a3.RequestURLParams = map[string]string{}
+	a3.RequestBody = "{\n  \"name\": \"test\"\n}"
+	h := NewMockHandler([]API{a1, a2, a3})
+	h.MatchQuery = true
+	h.MatchBody = true
+
+	for _, tt := range []struct {
+		method, target, body string
+		status               int
+	}{
+		{"GET", "/users/?limit=30", "", 200},
+		{"GET", "/users", "", 404},
+		{"DELETE", "/users", "", 405},
+		{"PUT", "/users", `{"name":"test"}`, 200},
+		{"PUT", "/users", `{"name":"other"}`, 404},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+		if w.Code != tt.status {
+			t.Fatalf("%s %s: got %d, want %d", tt.method, tt.target, w.Code, tt.status)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/users?limit=30", nil))
+	if w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatal(w.Header().Get("Content-Type"))
+	}
+	if w.Body.String() != a2.ResponseBody {
+		t.Fatal(w.Body.String())
+	}
+}
+
+func TestMockHandlerRecordedResponseHeaders(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Connection", "close")
+		w.Write([]byte(`{"id":1,"name":"test"}`))
+	}))
+	defer upstream.Close()
+	resp, err := http.Get(upstream.URL + "/users/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := NewAPI()
+	api.RequestMethod = "GET"
+	api.RequestPath = "/users/1"
+	api.ResponseStatusCode = resp.StatusCode
+	if err := api.ReadResponseHeader(resp.Header); err != nil {
+		t.Fatal(err)
+	}
+	// pretty printed body is longer than recorded Content-Length
+	if err := api.WrapResponseBody(body); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := api.ResponseHeaders["Content-Length"]; !ok {
+		t.Fatal("Content-Length must be recorded", api.ResponseHeaders)
+	}
+
+	mock := httptest.NewServer(NewMockHandler([]API{api}))
+	defer mock.Close()
+	resp, err = http.Get(mock.URL + "/users/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != api.ResponseBody {
+		t.Fatalf("got %q, want %q", got, api.ResponseBody)
+	}
+	if resp.ContentLength != int64(len(api.ResponseBody)) {
+		t.Fatal(resp.ContentLength)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatal(resp.Header)
+	}
+}
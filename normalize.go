@@ -0,0 +1,281 @@
+package apidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Normalizer return api whose volatile values are replaced with stable placeholders
+type Normalizer func(api API) API
+
+const (
+	// RFC3339Placeholder replace times normalized by NormalizeRFC3339
+	RFC3339Placeholder = "2006-01-02T15:04:05Z"
+	// UUIDPlaceholder replace uuids normalized by NormalizeUUID
+	UUIDPlaceholder = "00000000-0000-0000-0000-000000000000"
+)
+
+var (
+	rfc3339Pattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[Tt]\d{2}:\d{2}:\d{2}(\.\d+)?([Zz]|[+-]\d{2}:\d{2})`)
+	uuidPattern    = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	// jsonPathPattern match paths NormalizeJSONPath supports, keys and every element of arrays
+	jsonPathPattern = regexp.MustCompile(`^\$(\.[^.\[\]]+|\[\])*$`)
+)
+
+// NormalizeRegexp replace matches of pattern in path, host, bodies, headers and params with replacement
+// It panics if pattern is invalid, like regexp.MustCompile.
+func NormalizeRegexp(pattern, replacement string) Normalizer {
+	return normalizeRegexp(regexp.MustCompile(pattern), replacement)
+}
+
+func normalizeRegexp(re *regexp.Regexp, replacement string) Normalizer {
+	return func(api API) API {
+		return mapAPIStrings(api, func(s string) string {
+			return re.ReplaceAllString(s, replacement)
+		})
+	}
+}
+
+// NormalizeRFC3339 replace RFC3339 times like 2020-01-02T03:04:05.678+09:00 with RFC3339Placeholder
+func NormalizeRFC3339() Normalizer {
+	return normalizeRegexp(rfc3339Pattern, RFC3339Placeholder)
+}
+
+// NormalizeUUID replace uuids with UUIDPlaceholder
+func NormalizeUUID() Normalizer {
+	return normalizeRegexp(uuidPattern, UUIDPlaceholder)
+}
+
+// NormalizeHeader replace value of request and response header name like X-Request-Id with placeholder
+// Name is matched case-insensitively, e.g. x-request-id of HAR files.
+func NormalizeHeader(name, placeholder string) Normalizer {
+	name = http.CanonicalHeaderKey(name)
+	replace := func(headers map[string]string) map[string]string {
+		var out map[string]string
+		for key := range headers {
+			if http.CanonicalHeaderKey(key) != name {
+				continue
+			}
+			if out == nil {
+				out = copyStringMap(headers)
+			}
+			out[key] = placeholder
+		}
+		if out == nil {
+			return headers
+		}
+		return out
+	}
+	return func(api API) API {
+		api.RequestHeaders = replace(api.RequestHeaders)
+		api.ResponseHeaders = replace(api.ResponseHeaders)
+		return api
+	}
+}
+
+// NormalizeJSONPath replace values at path of json request and response bodies with placeholder
+// Path is written like $.users[].created_at, [] or [*] matches every element of array.
+// It panics if path has other syntax like $.users[0] or $..id, which would never match.
+func NormalizeJSONPath(path string, placeholder interface{}) Normalizer {
+	path = strings.Replace(path, "[*]", "[]", -1)
+	if !jsonPathPattern.MatchString(path) {
+		panic("apidoc: unsupported json path " + strconv.Quote(path))
+	}
+	return func(api API) API {
+		api.RequestBody = replaceJSONPath(api.RequestBody, path, placeholder)
+		api.ResponseBody = replaceJSONPath(api.ResponseBody, path, placeholder)
+		return api
+	}
+}
+
+// NormalizeTiming clear RequestStartedAt and Duration which differ every run
+func NormalizeTiming() Normalizer {
+	return func(api API) API {
+		api.RequestStartedAt = time.Time{}
+		api.Duration = 0
+		return api
+	}
+}
+
+func (p *Project) normalize(api API) API {
+	for _, n := range p.Normalizers {
+		api = n(api)
+	}
+	return api
+}
+
+// mapAPIStrings return api whose path, host, bodies, header values and params are mapped by f
+// Maps are copied, so that api given by caller is not modified.
+func mapAPIStrings(api API, f func(string) string) API {
+	requestPath, rawQuery := api.RequestPath, api.RequestRawQuery
+	api.RequestPath = f(api.RequestPath)
+	api.RequestHost = f(api.RequestHost)
+	api.RequestBody = f(api.RequestBody)
+	api.ResponseBody = f(api.ResponseBody)
+	api.RequestHeaders = mapStringMap(api.RequestHeaders, f)
+	api.ResponseHeaders = mapStringMap(api.ResponseHeaders, f)
+	escaped := func(s string) string {
+		v := unescapeQuery(s)
+		if nv := f(v); nv != v {
+			return url.QueryEscape(nv)
+		}
+		return s
+	}
+	api.RequestURLParams = mapStringMap(api.RequestURLParams, escaped)
+	api.RequestPostForms = mapStringMap(api.RequestPostForms, escaped)
+	if api.RequestRawQuery != "" {
+		params := strings.Split(api.RequestRawQuery, "&")
+		for i, param := range params {
+			if kv := strings.SplitN(param, "=", 2); len(kv) == 2 {
+				params[i] = kv[0] + "=" + escaped(kv[1])
+			}
+		}
+		api.RequestRawQuery = strings.Join(params, "&")
+	}
+	if api.RequestLine != "" && (api.RequestPath != requestPath || api.RequestRawQuery != rawQuery) {
+		uri := api.RequestPath
+		if api.RequestRawQuery != "" {
+			uri += "?" + api.RequestRawQuery
+		}
+		api.RequestLine = api.RequestMethod + " " + uri + " " + api.RequestProto
+	}
+	return api
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for key, value := range m {
+		out[key] = value
+	}
+	return out
+}
+
+func mapStringMap(m map[string]string, f func(string) string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for key, value := range m {
+		out[key] = f(value)
+	}
+	return out
+}
+
+// replaceJSONPath return body whose values at path are replaced with placeholder, keeping order of keys
+func replaceJSONPath(body, path string, placeholder interface{}) string {
+	if !isJSON(body) {
+		return body
+	}
+	p, err := marshalJSON(placeholder)
+	if err != nil {
+		return body
+	}
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	rw := &jsonRewriter{dec: dec, path: path, placeholder: p}
+	if err := rw.value("$"); err != nil || !rw.replaced {
+		return body
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return body
+	}
+	if !strings.Contains(body, "\n") {
+		return rw.buf.String()
+	}
+	out, err := PrettyPrint(rw.buf.Bytes())
+	if err != nil {
+		return body
+	}
+	return string(out)
+}
+
+// marshalJSON marshal v without escaping <, > and &
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// jsonRewriter copy json tokens to buf, replacing values at path with placeholder
+type jsonRewriter struct {
+	dec         *json.Decoder
+	buf         bytes.Buffer
+	path        string
+	placeholder []byte
+	replaced    bool
+}
+
+func (rw *jsonRewriter) value(current string) error {
+	if current == rw.path {
+		var skip json.RawMessage
+		if err := rw.dec.Decode(&skip); err != nil {
+			return err
+		}
+		rw.buf.Write(rw.placeholder)
+		rw.replaced = true
+		return nil
+	}
+	tok, err := rw.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		rw.buf.WriteByte('{')
+		for i := 0; rw.dec.More(); i++ {
+			keyTok, err := rw.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			b, err := marshalJSON(key)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				rw.buf.WriteByte(',')
+			}
+			rw.buf.Write(b)
+			rw.buf.WriteByte(':')
+			if err := rw.value(current + "." + key); err != nil {
+				return err
+			}
+		}
+		if _, err := rw.dec.Token(); err != nil {
+			return err
+		}
+		rw.buf.WriteByte('}')
+	case json.Delim('['):
+		rw.buf.WriteByte('[')
+		for i := 0; rw.dec.More(); i++ {
+			if i > 0 {
+				rw.buf.WriteByte(',')
+			}
+			if err := rw.value(current + "[]"); err != nil {
+				return err
+			}
+		}
+		if _, err := rw.dec.Token(); err != nil {
+			return err
+		}
+		rw.buf.WriteByte(']')
+	default:
+		b, err := marshalJSON(tok)
+		if err != nil {
+			return err
+		}
+		rw.buf.Write(b)
+	}
+	return nil
+}
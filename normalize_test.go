@@ -0,0 +1,175 @@
+package apidoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeRFC3339AndUUID(t *testing.T) {
+	api := newTestAPI()
+	api.RequestPath = "/users/3f2504e0-4f89-11d3-9a0c-0305e82c3301"
+	api.RequestRawQuery = "since=2020-01-02T03%3A04%3A05Z&limit=30"
+	api.RequestLine = "GET /users/3f2504e0-4f89-11d3-9a0c-0305e82c3301?since=2020-01-02T03%3A04%3A05Z&limit=30 HTTP/1.1"
+	api.RequestProto = "HTTP/1.1"
+	api.RequestURLParams["since"] = "2020-01-02T03%3A04%3A05Z"
+	api.ResponseHeaders["Date-Modified"] = " 2020-01-02T03:04:05.678+09:00\r"
+	api.ResponseBody = "{\n  \"id\": \"3f2504e0-4f89-11d3-9a0c-0305e82c3301\",\n  \"created_at\": \"2020-01-02T03:04:05Z\"\n}"
+
+	p := Project{Normalizers: []Normalizer{NormalizeRFC3339(), NormalizeUUID()}}
+	got := p.normalize(api)
+	if got.RequestPath != "/users/"+UUIDPlaceholder {
+		t.Fatal(got.RequestPath)
+	}
+	if got.RequestURLParams["since"] != "2006-01-02T15%3A04%3A05Z" || got.RequestURLParams["limit"] != "30" {
+		t.Fatal(got.RequestURLParams)
+	}
+	if got.RequestRawQuery != "since=2006-01-02T15%3A04%3A05Z&limit=30" {
+		t.Fatal(got.RequestRawQuery)
+	}
+	if want := "GET /users/" + UUIDPlaceholder + "?" + got.RequestRawQuery + " HTTP/1.1"; got.RequestLine != want {
+		t.Fatal(got.RequestLine)
+	}
+	if got.ResponseHeaders["Date-Modified"] != " "+RFC3339Placeholder+"\r" {
+		t.Fatalf("%q", got.ResponseHeaders["Date-Modified"])
+	}
+	if want := "{\n  \"id\": \"" + UUIDPlaceholder + "\",\n  \"created_at\": \"" + RFC3339Placeholder + "\"\n}"; got.ResponseBody != want {
+		t.Fatal(got.ResponseBody)
+	}
+	if api.ResponseHeaders["Date-Modified"] == got.ResponseHeaders["Date-Modified"] {
+		t.Fatal("given api must not be modified")
+	}
+}
+
+func TestNormalizeJSONPath(t *testing.T) {
+	api := newTestAPI()
+	api.RequestBody = `{"token":"abc<def","user":{"name":"a"}}`
+	api.ResponseBody = "{\n  \"users\": [\n    {\n      \"name\": \"b\",\n      \"id\": 1\n    },\n    {\n      \"name\": \"c\",\n      \"id\": 2\n    }\n  ],\n  \"token\": {\"value\": \"x\"}\n}"
+
+	got := NormalizeJSONPath("$.users[].id", 0)(api)
+	got = NormalizeJSONPath("$.token", "<token>")(got)
+	if got.RequestBody != `{"token":"<token>","user":{"name":"a"}}` {
+		t.Fatal(got.RequestBody)
+	}
+	want := "{\n  \"users\": [\n    {\n      \"name\": \"b\",\n      \"id\": 0\n    },\n    {\n      \"name\": \"c\",\n      \"id\": 0\n    }\n  ],\n  \"token\": \"<token>\"\n}"
+	if got.ResponseBody != want {
+		t.Fatal(got.ResponseBody)
+	}
+
+	body := `{"a": "x<y", "b": 1.50}`
+	if got := replaceJSONPath(body, "$.missing", "-"); got != body {
+		t.Fatal("body without path must not be changed", got)
+	}
+
+	if got := NormalizeJSONPath("$.users[*].id", 0)(NormalizeJSONPath("$.token", "<token>")(api)); got.ResponseBody != want {
+		t.Fatal("[*] must match every element", got.ResponseBody)
+	}
+}
+
+func TestNormalizeJSONPathUnsupported(t *testing.T) {
+	for _, path := range []string{"users[].id", "$.users[0].id", "$..id", "$.users.", "$['token']"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s must panic", path)
+				}
+			}()
+			NormalizeJSONPath(path, 0)
+		}()
+	}
+}
+
+func TestGenNormalizes(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	if err := Init(Project{
+		Store: &MemoryStore{}, DisableOutputs: true,
+		Normalizers: []Normalizer{
+			NormalizeHeader("X-Request-Id", "<request-id>"),
+			NormalizeRegexp(`tok_[a-z0-9]+`, "tok_xxx"),
+			NormalizeTiming(),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	api := newTestAPI()
+	api.RequestStartedAt = time.Now()
+	api.Duration = time.Second
+	api.ResponseHeaders["X-Request-Id"] = " 8d7f\r"
+	api.ResponseBody = `{"token": "tok_1a2b"}`
+	if err := Gen(api); err != nil {
+		t.Fatal(err)
+	}
+	got := snapshot().APIs[0]
+	if got.ResponseHeaders["X-Request-Id"] != "<request-id>" || got.ResponseBody != `{"token": "tok_xxx"}` {
+		t.Fatalf("%+v", got)
+	}
+	if !got.RequestStartedAt.IsZero() || got.Duration != 0 {
+		t.Fatalf("%+v", got)
+	}
+}
+
+func TestGenRecordsVolatile(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	for _, normalizeTiming := range []bool{false, true} {
+		p = Project{Store: &MemoryStore{}, DisableOutputs: true}
+		if normalizeTiming {
+			p.Normalizers = []Normalizer{NormalizeTiming()}
+		}
+		api := newTestAPI()
+		api.RequestHost = "127.0.0.1:34567"
+		api.RequestStartedAt = time.Now()
+		api.Duration = time.Second
+		if err := Gen(api); err != nil {
+			t.Fatal(err)
+		}
+		got := p.APIs[0]
+		if got.RequestHost != api.RequestHost {
+			t.Fatal("host must be recorded", got.RequestHost)
+		}
+		cleared := got.RequestStartedAt.IsZero() && got.Duration == 0
+		if cleared != normalizeTiming {
+			t.Fatalf("NormalizeTiming %v: %+v", normalizeTiming, got)
+		}
+		var buf bytes.Buffer
+		if err := p.WriteJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		hasTiming := strings.Contains(buf.String(), "request_started_at") || strings.Contains(buf.String(), `"duration"`)
+		if hasTiming == normalizeTiming {
+			t.Fatalf("NormalizeTiming %v: %s", normalizeTiming, buf.String())
+		}
+	}
+}
+
+func TestNormalizeHostAndHeaderCase(t *testing.T) {
+	api := newTestAPI()
+	api.RequestHost = "127.0.0.1:34567"
+	api.RequestHeaders["x-request-id"] = " 8d7f\r"
+	api = NormalizeRegexp(`:\d+$`, ":8080")(api)
+	api = NormalizeHeader("X-Request-Id", "<request-id>")(api)
+	if api.RequestHost != "127.0.0.1:8080" {
+		t.Fatal(api.RequestHost)
+	}
+	if api.RequestHeaders["x-request-id"] != "<request-id>" {
+		t.Fatal(api.RequestHeaders)
+	}
+}
+
+func TestNormalizeTimingGeneratedAt(t *testing.T) {
+	api := NormalizeTiming()(newTestAPI())
+	p := Project{APIs: []API{api}}
+	var buf bytes.Buffer
+	if err := p.WriteHTML(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "Generated at") || strings.Contains(buf.String(), "0001-01-01") {
+		t.Fatal("zero generated time must not be rendered")
+	}
+}
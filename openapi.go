@@ -0,0 +1,216 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type openAPIDocument struct {
+	OpenAPI string                                  `json:"openapi"`
+	Info    openAPIInfo                             `json:"info"`
+	Paths   map[string]map[string]*openAPIOperation `json:"paths"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIOperation struct {
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name    string         `json:"name"`
+	In      string         `json:"in"`
+	Schema  *openAPISchema `json:"schema"`
+	Example string         `json:"example,omitempty"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Headers     map[string]openAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIHeader struct {
+	Schema  *openAPISchema `json:"schema"`
+	Example string         `json:"example,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema  *openAPISchema `json:"schema,omitempty"`
+	Example interface{}    `json:"example,omitempty"`
+}
+
+type openAPISchema struct {
+	Type       string                    `json:"type,omitempty"`
+	Nullable   bool                      `json:"nullable,omitempty"`
+	Properties map[string]*openAPISchema `json:"properties,omitempty"`
+	Items      *openAPISchema            `json:"items,omitempty"`
+}
+
+// WriteOpenAPI write OpenAPI 3.0 document to w
+// It returns error if same method and path is recorded in several versions, render them with Output.Version.
+func (p *Project) WriteOpenAPI(w io.Writer) error {
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:   p.DocumentTitle,
+			Version: "1.0.0",
+		},
+		Paths: map[string]map[string]*openAPIOperation{},
+	}
+	if versions := p.Versions(); len(versions) == 1 {
+		doc.Info.Version = versions[0]
+	}
+	versions := map[string]string{}
+	for _, api := range p.APIs {
+		key := api.RequestMethod + " " + api.RequestPath
+		if version, ok := versions[key]; ok && version != api.Version {
+			return fmt.Errorf("apidoc: %s is recorded in versions %q and %q, render openapi with Output.Version", key, version, api.Version)
+		}
+		versions[key] = api.Version
+		addOpenAPIOperation(doc.Paths, api)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	out, err := PrettyPrint(b)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func addOpenAPIOperation(paths map[string]map[string]*openAPIOperation, api API) {
+	item, ok := paths[api.RequestPath]
+	if !ok {
+		item = map[string]*openAPIOperation{}
+		paths[api.RequestPath] = item
+	}
+	method := strings.ToLower(api.RequestMethod)
+	op, ok := item[method]
+	if !ok {
+		op = &openAPIOperation{Responses: map[string]*openAPIResponse{}}
+		item[method] = op
+	}
+
+	if op.Parameters == nil {
+		for _, key := range sortedKeys(api.RequestURLParams) {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:    key,
+				In:      "query",
+				Schema:  &openAPISchema{Type: "string"},
+				Example: api.RequestURLParams[key],
+			})
+		}
+		for _, key := range sortedKeys(api.RequestHeaders) {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:    key,
+				In:      "header",
+				Schema:  &openAPISchema{Type: "string"},
+				Example: headerValue(api.RequestHeaders[key]),
+			})
+		}
+	}
+
+	if op.RequestBody == nil {
+		if mt, ok := requestOpenAPIMediaType(api); ok {
+			op.RequestBody = &openAPIRequestBody{
+				Content: map[string]openAPIMediaType{api.requestContentType(): mt},
+			}
+		}
+	}
+
+	res := &openAPIResponse{Description: http.StatusText(api.ResponseStatusCode)}
+	if res.Description == "" {
+		res.Description = "Response"
+	}
+	for _, key := range sortedKeys(api.ResponseHeaders) {
+		if res.Headers == nil {
+			res.Headers = map[string]openAPIHeader{}
+		}
+		res.Headers[key] = openAPIHeader{
+			Schema:  &openAPISchema{Type: "string"},
+			Example: headerValue(api.ResponseHeaders[key]),
+		}
+	}
+	if api.ResponseBody != "" {
+		contentType := api.responseContentType()
+		if contentType == "" {
+			contentType = "text/plain"
+		}
+		res.Content = map[string]openAPIMediaType{contentType: bodyOpenAPIMediaType(api.ResponseBody)}
+	}
+	op.Responses[strconv.Itoa(api.ResponseStatusCode)] = res
+}
+
+func requestOpenAPIMediaType(api API) (openAPIMediaType, bool) {
+	if len(api.RequestPostForms) > 0 {
+		schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+		example := map[string]string{}
+		for key, value := range api.RequestPostForms {
+			schema.Properties[key] = &openAPISchema{Type: "string"}
+			example[key] = value
+		}
+		return openAPIMediaType{Schema: schema, Example: example}, true
+	}
+	if api.RequestBody != "" {
+		return bodyOpenAPIMediaType(api.RequestBody), true
+	}
+	return openAPIMediaType{}, false
+}
+
+func bodyOpenAPIMediaType(body string) openAPIMediaType {
+	var v interface{}
+	if isJSON(body) && json.Unmarshal([]byte(body), &v) == nil {
+		return openAPIMediaType{Schema: openAPISchemaOf(v), Example: v}
+	}
+	return openAPIMediaType{Schema: &openAPISchema{Type: "string"}, Example: body}
+}
+
+func openAPISchemaOf(v interface{}) *openAPISchema {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		s := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+		for key, value := range t {
+			s.Properties[key] = openAPISchemaOf(value)
+		}
+		return s
+	case []interface{}:
+		s := &openAPISchema{Type: "array"}
+		if len(t) > 0 {
+			s.Items = openAPISchemaOf(t[0])
+		}
+		return s
+	case nil:
+		// null is not a type in OpenAPI 3.0
+		return &openAPISchema{Nullable: true}
+	}
+	return &openAPISchema{Type: jsonTypeOf(v)}
+}
+
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(headerValue(contentType))
+	if err != nil {
+		return headerValue(contentType)
+	}
+	return mt
+}
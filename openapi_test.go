@@ -0,0 +1,67 @@
+package apidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteOpenAPI(t *testing.T) {
+	p := Project{
+		DocumentTitle: "apidoc-test",
+		APIs:          []API{newTestAPI()},
+	}
+	var b bytes.Buffer
+	if err := p.WriteOpenAPI(&b); err != nil {
+		t.Fatal(err)
+	}
+	var doc openAPIDocument
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	op := doc.Paths["/users"]["get"]
+	if op == nil {
+		t.Fatal("GET /users is not found")
+	}
+	res := op.Responses["200"]
+	if res == nil {
+		t.Fatal("200 response is not found")
+	}
+	schema := res.Content["application/json"].Schema
+	if schema.Properties["users"].Type != "array" {
+		t.Fatal("users is not array")
+	}
+	if schema.Properties["users"].Items.Properties["id"].Type != "number" {
+		t.Fatal("users.id is not number")
+	}
+}
+
+func TestWriteOpenAPINullAndVersions(t *testing.T) {
+	api := newTestAPI()
+	api.ResponseBody = `{"deleted_at": null}`
+	p := Project{APIs: []API{api}}
+	var b bytes.Buffer
+	if err := p.WriteOpenAPI(&b); err != nil {
+		t.Fatal(err)
+	}
+	var doc openAPIDocument
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	schema := doc.Paths["/users"]["get"].Responses["200"].Content["application/json"].Schema.Properties["deleted_at"]
+	if schema.Type != "" || !schema.Nullable {
+		t.Fatalf("null must be nullable without type %+v", schema)
+	}
+
+	v1 := newTestAPI()
+	v1.Version = "v1"
+	v2 := newTestAPI()
+	v2.Version = "v2"
+	p = Project{APIs: []API{v1, v2}}
+	if err := p.WriteOpenAPI(&bytes.Buffer{}); err == nil {
+		t.Fatal("same operation of versions must be error")
+	}
+	if err := p.ForVersion("v2").WriteOpenAPI(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+}
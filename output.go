@@ -0,0 +1,144 @@
+package apidoc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Format output format name
+type Format string
+
+const (
+	// FormatHTML render html with template
+	FormatHTML Format = "html"
+	// FormatMarkdown render markdown
+	FormatMarkdown Format = "markdown"
+	// FormatOpenAPI render OpenAPI 3.0 json
+	FormatOpenAPI Format = "openapi"
+	// FormatJSON render apis json same as document json file
+	FormatJSON Format = "json"
+	// FormatPostman render Postman Collection v2.1
+	FormatPostman Format = "postman"
+	// FormatHAR render HAR 1.2
+	FormatHAR Format = "har"
+	// FormatSite write multi-page site into directory at Output.Path, see WriteSite
+	FormatSite Format = "site"
+)
+
+// Output has output setting
+type Output struct {
+	Format Format
+	Path   string
+	// TemplatePath is used by FormatHTML only
+	TemplatePath string
+	// Version render apis of the version only if not empty
+	Version string
+}
+
+type renderer func(p *Project, w io.Writer, o Output) error
+
+var renderers = map[Format]renderer{
+	FormatHTML: func(p *Project, w io.Writer, o Output) error {
+		return p.WriteHTML(w, o.TemplatePath)
+	},
+	FormatMarkdown: func(p *Project, w io.Writer, o Output) error {
+		return p.WriteMarkdown(w)
+	},
+	FormatOpenAPI: func(p *Project, w io.Writer, o Output) error {
+		return p.WriteOpenAPI(w)
+	},
+	FormatJSON: func(p *Project, w io.Writer, o Output) error {
+		return p.WriteJSON(w)
+	},
+	FormatPostman: func(p *Project, w io.Writer, o Output) error {
+		return p.WritePostman(w)
+	},
+	FormatHAR: func(p *Project, w io.Writer, o Output) error {
+		return p.WriteHAR(w)
+	},
+}
+
+// Render write apis to w with output format
+func (p *Project) Render(w io.Writer, o Output) error {
+	if o.Format == FormatSite {
+		return fmt.Errorf("apidoc: %s output is a directory, use WriteSite", o.Format)
+	}
+	r, ok := renderers[o.Format]
+	if !ok {
+		return fmt.Errorf("apidoc: unknown output format %q", o.Format)
+	}
+	return r(p, w, o)
+}
+
+func (p *Project) getOutputs() []Output {
+	if p.DisableOutputs {
+		return nil
+	}
+	if len(p.Outputs) > 0 {
+		return p.Outputs
+	}
+	return []Output{{
+		Format:       FormatHTML,
+		Path:         p.getDocumentPath(),
+		TemplatePath: p.TemplatePath,
+	}}
+}
+
+func (p *Project) writeOutputFile(o Output) error {
+	vp := p
+	if o.Version != "" {
+		vp = p.ForVersion(o.Version)
+	}
+	if o.Format == FormatSite {
+		return vp.WriteSite(o.Path)
+	}
+	return writeFileAtomic(o.Path, func(w io.Writer) error {
+		return vp.Render(w, o)
+	})
+}
+
+func (p *Project) writeOutputFiles() error {
+	for _, o := range p.getOutputs() {
+		if err := p.writeOutputFile(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Project) deleteOutputFiles() error {
+	for _, o := range p.getOutputs() {
+		if o.Format == FormatSite {
+			if err := deleteSite(o.Path); err != nil {
+				return err
+			}
+			continue
+		}
+		filePath, err := filepath.Abs(o.Path)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// headerValue trim spaces and CR left by ReadRequestHeader and ReadResponseHeader
+func headerValue(v string) string {
+	return strings.TrimSpace(v)
+}
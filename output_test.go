@@ -0,0 +1,91 @@
+package apidoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestAPI() API {
+	api := NewAPI()
+	api.RequestMethod = "GET"
+	api.RequestPath = "/users"
+	api.RequestURLParams["limit"] = "30"
+	api.ResponseHeaders["Content-Type"] = " application/json; charset=utf-8\r"
+	api.ResponseStatusCode = 200
+	api.ResponseBody = "{\n  \"users\": [\n    {\n      \"id\": 1,\n      \"name\": \"test1\"\n    }\n  ]\n}"
+	return api
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	p := Project{APIs: []API{newTestAPI()}}
+	if err := p.Render(&bytes.Buffer{}, Output{Format: "unknown"}); err == nil {
+		t.Fatal("unknown format must be error")
+	}
+}
+
+func TestWriteOutputFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := Project{
+		DocumentTitle: "apidoc-test",
+		TemplatePath:  "default.tpl.html",
+		Outputs: []Output{
+			{Format: FormatHTML, Path: filepath.Join(dir, "apidoc.html")},
+			{Format: FormatMarkdown, Path: filepath.Join(dir, "apidoc.md")},
+			{Format: FormatOpenAPI, Path: filepath.Join(dir, "openapi.json")},
+			{Format: FormatJSON, Path: filepath.Join(dir, "apis.json")},
+		},
+		APIs: []API{newTestAPI()},
+	}
+	if err := p.writeOutputFiles(); err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range p.Outputs {
+		if _, err := os.Stat(o.Path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.deleteOutputFiles(); err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range p.Outputs {
+		if _, err := os.Stat(o.Path); !os.IsNotExist(err) {
+			t.Fatalf("%s is not deleted", o.Path)
+		}
+	}
+}
+
+func TestDefaultOutputWithStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	documentPath := filepath.Join(dir, "apidoc.html")
+
+	p := Project{DocumentPath: documentPath, Store: &MemoryStore{}}
+	p.appendAPI(newTestAPI())
+	if err := p.save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(documentPath); err != nil {
+		t.Fatal("html must be written with Store", err)
+	}
+	if _, err := os.Stat(documentPath + ".json"); !os.IsNotExist(err) {
+		t.Fatal("json must be saved to Store only")
+	}
+
+	if outputs := (&Project{Store: &MemoryStore{}, DisableOutputs: true}).getOutputs(); len(outputs) != 0 {
+		t.Fatal("DisableOutputs must render nothing", outputs)
+	}
+	if outputs := (&Project{Outputs: []Output{}}).getOutputs(); len(outputs) != 1 || outputs[0].Format != FormatHTML {
+		t.Fatal("empty Outputs must render default html", outputs)
+	}
+}
@@ -0,0 +1,241 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []*postmanItem    `json:"item"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name     string            `json:"name"`
+	Item     []*postmanItem    `json:"item,omitempty"`
+	Request  *postmanRequest   `json:"request,omitempty"`
+	Response []postmanResponse `json:"response,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Auth   *postmanAuth      `json:"auth,omitempty"`
+	Header []postmanKeyValue `json:"header"`
+	URL    postmanURL        `json:"url"`
+	Body   *postmanBody      `json:"body,omitempty"`
+}
+
+type postmanURL struct {
+	Raw   string            `json:"raw"`
+	Host  []string          `json:"host"`
+	Path  []string          `json:"path,omitempty"`
+	Query []postmanKeyValue `json:"query,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string              `json:"mode"`
+	Raw        string              `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue   `json:"urlencoded,omitempty"`
+	Options    *postmanBodyOptions `json:"options,omitempty"`
+}
+
+type postmanBodyOptions struct {
+	Raw postmanRawOptions `json:"raw"`
+}
+
+type postmanRawOptions struct {
+	Language string `json:"language"`
+}
+
+type postmanResponse struct {
+	Name            string            `json:"name"`
+	OriginalRequest *postmanRequest   `json:"originalRequest"`
+	Status          string            `json:"status"`
+	Code            int               `json:"code"`
+	Header          []postmanKeyValue `json:"header"`
+	Body            string            `json:"body"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+}
+
+type postmanKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+const (
+	postmanBaseURLVariable       = "baseUrl"
+	postmanAuthTokenVariable     = "authToken"
+	postmanAuthorizationVariable = "authorization"
+)
+
+// postmanAuthVariables name each distinct credential as authToken, authToken2... for Bearer,
+// and authorization, authorization2... for other Authorization headers
+type postmanAuthVariables struct {
+	names     map[string]string
+	variables []postmanKeyValue
+	counts    map[string]int
+}
+
+func newPostmanAuthVariables() *postmanAuthVariables {
+	return &postmanAuthVariables{names: map[string]string{}, counts: map[string]int{}}
+}
+
+// name return variable name of value, defining it if new
+func (v *postmanAuthVariables) name(prefix, value string) string {
+	if name, ok := v.names[prefix+" "+value]; ok {
+		return name
+	}
+	v.counts[prefix]++
+	name := prefix
+	if n := v.counts[prefix]; n > 1 {
+		name += strconv.Itoa(n)
+	}
+	v.names[prefix+" "+value] = name
+	v.variables = append(v.variables, postmanKeyValue{Key: name, Value: value})
+	return name
+}
+
+// WritePostman write Postman Collection v2.1 to w
+// Folders are named by API.GroupName.
+// Auth is decided per request: Bearer tokens are sent by request auth, other Authorization headers by header,
+// each distinct credential referencing its own collection variable.
+func (p *Project) WritePostman(w io.Writer) error {
+	c := postmanCollection{
+		Info: postmanInfo{
+			Name:   p.DocumentTitle,
+			Schema: postmanSchema,
+		},
+		Item: []*postmanItem{},
+	}
+	baseURL := p.baseURL()
+	if baseURL == "" {
+		baseURL = "http://localhost"
+	}
+	c.Variable = append(c.Variable, postmanKeyValue{Key: postmanBaseURLVariable, Value: strings.TrimRight(baseURL, "/")})
+
+	authVars := newPostmanAuthVariables()
+	folders := map[string]*postmanItem{}
+	requests := map[string]*postmanItem{}
+	for _, api := range p.APIs {
+		name := api.GroupName()
+		folder, ok := folders[name]
+		if !ok {
+			folder = &postmanItem{Name: name, Item: []*postmanItem{}}
+			folders[name] = folder
+			c.Item = append(c.Item, folder)
+		}
+
+		req := newPostmanRequest(api, authVars)
+		key := api.RequestMethod + " " + api.RequestPath
+		if api.Version != "" {
+			key += " " + api.Version
+		}
+		item, ok := requests[key]
+		if !ok {
+			item = &postmanItem{Name: key, Request: req}
+			requests[key] = item
+			folder.Item = append(folder.Item, item)
+		}
+		item.Response = append(item.Response, newPostmanResponse(api, req))
+	}
+
+	c.Variable = append(c.Variable, authVars.variables...)
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	out, err := PrettyPrint(b)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func newPostmanRequest(api API, authVars *postmanAuthVariables) *postmanRequest {
+	req := &postmanRequest{
+		Method: api.RequestMethod,
+		Header: []postmanKeyValue{},
+		URL: postmanURL{
+			Raw:  "{{" + postmanBaseURLVariable + "}}" + api.RequestPath,
+			Host: []string{"{{" + postmanBaseURLVariable + "}}"},
+		},
+	}
+	for _, segment := range strings.Split(api.RequestPath, "/") {
+		if segment != "" {
+			req.URL.Path = append(req.URL.Path, segment)
+		}
+	}
+	for i, key := range sortedKeys(api.RequestURLParams) {
+		req.URL.Query = append(req.URL.Query, postmanKeyValue{Key: key, Value: api.RequestURLParams[key]})
+		if i == 0 {
+			req.URL.Raw += "?"
+		} else {
+			req.URL.Raw += "&"
+		}
+		req.URL.Raw += key + "=" + api.RequestURLParams[key]
+	}
+	for _, key := range sortedKeys(api.RequestHeaders) {
+		value := headerValue(api.RequestHeaders[key])
+		if key == "Authorization" {
+			if strings.HasPrefix(value, "Bearer ") {
+				name := authVars.name(postmanAuthTokenVariable, strings.TrimPrefix(value, "Bearer "))
+				req.Auth = &postmanAuth{
+					Type:   "bearer",
+					Bearer: []postmanKeyValue{{Key: "token", Value: "{{" + name + "}}", Type: "string"}},
+				}
+				continue
+			}
+			value = "{{" + authVars.name(postmanAuthorizationVariable, value) + "}}"
+		}
+		req.Header = append(req.Header, postmanKeyValue{Key: key, Value: value})
+	}
+	switch {
+	case len(api.RequestPostForms) > 0:
+		req.Body = &postmanBody{Mode: "urlencoded"}
+		for _, key := range sortedKeys(api.RequestPostForms) {
+			req.Body.URLEncoded = append(req.Body.URLEncoded, postmanKeyValue{Key: key, Value: api.RequestPostForms[key]})
+		}
+	case api.RequestBody != "":
+		req.Body = &postmanBody{Mode: "raw", Raw: api.RequestBody}
+		if isJSON(api.RequestBody) {
+			req.Body.Options = &postmanBodyOptions{Raw: postmanRawOptions{Language: "json"}}
+		}
+	}
+	return req
+}
+
+func newPostmanResponse(api API, req *postmanRequest) postmanResponse {
+	res := postmanResponse{
+		Name:            http.StatusText(api.ResponseStatusCode),
+		OriginalRequest: req,
+		Status:          http.StatusText(api.ResponseStatusCode),
+		Code:            api.ResponseStatusCode,
+		Header:          []postmanKeyValue{},
+		Body:            api.ResponseBody,
+	}
+	if res.Name == "" {
+		res.Name = "Response"
+	}
+	for _, key := range sortedKeys(api.ResponseHeaders) {
+		res.Header = append(res.Header, postmanKeyValue{Key: key, Value: headerValue(api.ResponseHeaders[key])})
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+package apidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWritePostman(t *testing.T) {
+	a1 := newTestAPI()
+	a1.RequestHeaders["Authorization"] = " Bearer secret\r"
+	a2 := newTestAPI()
+	a2.ResponseStatusCode = 400
+	a3 := newTestAPI()
+	a3.RequestPath = "/items"
+	a3.Tags = []string{"stock"}
+	p := Project{
+		DocumentTitle: "apidoc-test",
+		BaseURL:       "http://localhost:8080/",
+		APIs:          []API{a1, a2, a3},
+	}
+	var b bytes.Buffer
+	if err := p.WritePostman(&b); err != nil {
+		t.Fatal(err)
+	}
+	var c postmanCollection
+	if err := json.Unmarshal(b.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Item) != 2 || c.Item[0].Name != "users" || c.Item[1].Name != "stock" {
+		t.Fatalf("unexpected folders %+v", c.Item)
+	}
+	users := c.Item[0].Item
+	if len(users) != 1 || len(users[0].Response) != 2 {
+		t.Fatal("GET /users must have 2 responses")
+	}
+	if users[0].Request.URL.Raw != "{{baseUrl}}/users?limit=30" {
+		t.Fatal(users[0].Request.URL.Raw)
+	}
+	if auth := users[0].Request.Auth; auth == nil || auth.Type != "bearer" || auth.Bearer[0].Value != "{{authToken}}" {
+		t.Fatal("bearer auth is not set")
+	}
+	vars := map[string]string{}
+	for _, v := range c.Variable {
+		vars[v.Key] = v.Value
+	}
+	if vars["baseUrl"] != "http://localhost:8080" || vars["authToken"] != "secret" {
+		t.Fatalf("unexpected variables %+v", vars)
+	}
+}
+
+func TestWritePostmanMixedAuth(t *testing.T) {
+	basic := newTestAPI()
+	basic.RequestPath = "/login"
+	basic.RequestHeaders["Authorization"] = " Basic dXNlcjpwYXNz\r"
+	bearer := newTestAPI()
+	bearer.RequestHeaders["Authorization"] = " Bearer token1\r"
+	other := newTestAPI()
+	other.RequestPath = "/items"
+	other.RequestHeaders["Authorization"] = " Bearer token2\r"
+	anonymous := newTestAPI()
+	anonymous.RequestPath = "/health"
+	p := Project{APIs: []API{basic, bearer, other, anonymous}}
+	var b bytes.Buffer
+	if err := p.WritePostman(&b); err != nil {
+		t.Fatal(err)
+	}
+	var c postmanCollection
+	if err := json.Unmarshal(b.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+	requests := map[string]*postmanRequest{}
+	for _, folder := range c.Item {
+		for _, item := range folder.Item {
+			requests[item.Name] = item.Request
+		}
+	}
+	vars := map[string]string{}
+	for _, v := range c.Variable {
+		vars[v.Key] = v.Value
+	}
+
+	login := requests["GET /login"]
+	if login.Auth != nil || len(login.Header) != 1 || login.Header[0].Value != "{{authorization}}" {
+		t.Fatalf("%+v", login)
+	}
+	if vars["authorization"] != "Basic dXNlcjpwYXNz" {
+		t.Fatalf("%+v", vars)
+	}
+	if auth := requests["GET /users"].Auth; auth == nil || auth.Bearer[0].Value != "{{authToken}}" || vars["authToken"] != "token1" {
+		t.Fatalf("%+v %+v", auth, vars)
+	}
+	if auth := requests["GET /items"].Auth; auth == nil || auth.Bearer[0].Value != "{{authToken2}}" || vars["authToken2"] != "token2" {
+		t.Fatalf("%+v %+v", auth, vars)
+	}
+	if requests["GET /health"].Auth != nil {
+		t.Fatal("request without Authorization must not have auth")
+	}
+}
@@ -2,21 +2,50 @@ package apidoc
 
 import (
 	"encoding/json"
-	"go/build"
 	"html/template"
 	"io"
 	"os"
-	"path"
 	"path/filepath"
+	"strings"
 )
 
 // Project has project setting
 type Project struct {
 	DocumentTitle string
-	DocumentPath  string
-	TemplatePath  string
+	// Description is rendered as markdown in html document
+	Description  string
+	DocumentPath string
+	TemplatePath string
+	// Funcs add or override TemplateFuncs in html templates
+	Funcs template.FuncMap
+	// BaseURL is used to build requests in exported documents
+	BaseURL string
+	// Versioner set version of apis which are not annotated
+	Versioner Versioner
+	// SortBy order apis in document json file and documents, default is SortByPath
+	SortBy SortBy
+	// Normalizers replace volatile values like times and ids of apis recorded by Gen and ImportHAR, in order
+	Normalizers []Normalizer
+	// ExcludePaths are path prefixes not recorded by Gen, e.g. /_apidoc where Handler is mounted
+	ExcludePaths []string
+
+	// Outputs render documents to each path, default is html at DocumentPath
+	Outputs []Output
+	// DisableOutputs render no documents, e.g. when apis are only captured by Store
+	DisableOutputs bool
+
+	// RecoverCorruptDocument move corrupt document json file aside and start with no apis
+	RecoverCorruptDocument bool
+
+	// Store persist apis, default is FileStore at DocumentPath + ".json"
+	Store Store
 
 	APIs []API
+
+	// unsaved are apis recorded since Init or last save, only they are merged into Store
+	unsaved []API
+	// version is set by ForVersion
+	version string
 }
 
 func (p *Project) hasDocumentPath() bool {
@@ -34,79 +63,78 @@ func (p *Project) getDocumentJSONPath() string {
 	return p.getDocumentPath() + ".json"
 }
 
-func (p *Project) hasTemplatePath() bool {
-	return p.TemplatePath != ""
-}
-
-func findAppPath() string {
-	const appName = "github.com/gotokatsuya/apidoc"
-	appPkg, err := build.Import(appName, "", build.FindOnly)
-	if err != nil {
-		return ""
-	}
-	return path.Join(appPkg.SrcRoot, appName)
-}
-
-func (p *Project) getTemplatePath() string {
-	if p.hasTemplatePath() {
-		return p.TemplatePath
+func (p *Project) getStore() Store {
+	if p.Store != nil {
+		return p.Store
 	}
-	return path.Join(findAppPath(), "default.tpl.html")
-}
-
-func (p *Project) openDocumentJSONFile() (*os.File, error) {
-	filePath, err := filepath.Abs(p.getDocumentJSONPath())
-	if err != nil {
-		return nil, err
-	}
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+	return &FileStore{
+		Path:           p.getDocumentJSONPath(),
+		RecoverCorrupt: p.RecoverCorruptDocument,
 	}
-	return file, nil
 }
 
-func (p *Project) loadDocumentJSONFile() error {
-	file, err := p.openDocumentJSONFile()
-	defer file.Close()
+func (p *Project) load() error {
+	apis, err := p.getStore().Load()
 	if err != nil {
 		return err
 	}
-	if err := json.NewDecoder(io.Reader(file)).Decode(&p.APIs); err != nil {
-		return err
+	if apis != nil {
+		p.APIs = apis
+		SortAPIs(p.APIs, p.SortBy)
 	}
 	return nil
 }
 
-func (p *Project) createDocumentJSONFile() (*os.File, error) {
-	filePath, err := filepath.Abs(p.getDocumentJSONPath())
-	if err != nil {
+// ReadAPIs read apis json written by Gen
+func ReadAPIs(r io.Reader) ([]API, error) {
+	apis := []API{}
+	if err := json.NewDecoder(r).Decode(&apis); err != nil {
 		return nil, err
 	}
-	file, err := os.Create(filePath)
+	return apis, nil
+}
+
+// LoadAPIs load apis from document json file
+func LoadAPIs(filePath string) ([]API, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
-	return file, nil
-}
-
-func (p *Project) deleteDocumentJSONFile() error {
-	filePath, err := filepath.Abs(p.getDocumentJSONPath())
+	defer file.Close()
+	return ReadAPIs(file)
+}
+
+// save merge apis recorded since last save into stored apis and write them, then render outputs
+// Apis loaded before are not merged back, so that they don't overwrite newer ones saved by other processes.
+// Stores implementing Locker are locked meanwhile, so that processes sharing it don't lose apis.
+func (p *Project) save() error {
+	store := p.getStore()
+	if locker, ok := store.(Locker); ok {
+		unlock, err := locker.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	apis, err := store.Load()
 	if err != nil {
 		return err
 	}
-	if err := os.Remove(filePath); err != nil {
+	if apis == nil {
+		// nothing is stored, e.g. WriterStore
+		apis = p.APIs
+	}
+	p.APIs = Merge(apis, p.unsaved)
+	SortAPIs(p.APIs, p.SortBy)
+	if err := store.Save(p.APIs); err != nil {
 		return err
 	}
-	return nil
+	p.unsaved = nil
+	return p.writeOutputFiles()
 }
 
-func (p *Project) writeDocumentJSONFile() error {
-	file, err := p.createDocumentJSONFile()
-	defer file.Close()
-	if err != nil {
-		return err
-	}
+// WriteJSON write apis json to w
+func (p *Project) WriteJSON(w io.Writer) error {
 	b, err := json.Marshal(p.APIs)
 	if err != nil {
 		return err
@@ -115,24 +143,12 @@ func (p *Project) writeDocumentJSONFile() error {
 	if err != nil {
 		return err
 	}
-	if _, err := file.Write(out); err != nil {
+	if _, err := w.Write(out); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (p *Project) createDocumentFile() (*os.File, error) {
-	filePath, err := filepath.Abs(p.getDocumentPath())
-	if err != nil {
-		return nil, err
-	}
-	file, err := os.Create(filePath)
-	if err != nil {
-		return nil, err
-	}
-	return file, nil
-}
-
 func (p *Project) deleteDocumentFile() error {
 	filePath, err := filepath.Abs(p.getDocumentPath())
 	if err != nil {
@@ -144,20 +160,66 @@ func (p *Project) deleteDocumentFile() error {
 	return nil
 }
 
-func (p *Project) writeDocumentFile() error {
-	t := template.Must(template.ParseFiles(p.getTemplatePath()))
-	file, err := p.createDocumentFile()
-	defer file.Close()
+// WriteHTML write html document to w, TemplatePath or embedded default template is used if templatePath is empty
+// The default template inlines css and js, so that the document is a single file readable offline.
+// Templates can use TemplateFuncs and data below.
+//
+//	.title       DocumentTitle, with version if rendered for a version
+//	.description Description in markdown
+//	.baseURL     BaseURL
+//	.project     *Project
+//	.apis        []API
+//	.groups      []Group by tag or resource, see API.GroupName
+//	.versions    sorted versions of apis
+//	.version     version rendered by Output.Version, empty if all versions
+//	.generatedAt time.Time of SOURCE_DATE_EPOCH, zero if not set
+//	.css, .js    assets of the default template
+func (p *Project) WriteHTML(w io.Writer, templatePath string) error {
+	if templatePath == "" {
+		templatePath = p.TemplatePath
+	}
+	t, err := p.parseTemplate(templatePath)
 	if err != nil {
 		return err
 	}
-	return t.Execute(io.Writer(file), map[string]interface{}{
-		"title": p.DocumentTitle,
-		"apis":  p.APIs,
-	})
+	return t.Execute(w, p.templateData())
+}
+
+// baseURL return BaseURL or origin of first recorded api
+func (p *Project) baseURL() string {
+	if p.BaseURL != "" {
+		return p.BaseURL
+	}
+	for _, api := range p.APIs {
+		if origin := api.origin(); origin != "" {
+			return origin
+		}
+	}
+	return ""
+}
+
+// excludes return true if path is under one of ExcludePaths
+func (p *Project) excludes(requestPath string) bool {
+	for _, prefix := range p.ExcludePaths {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if requestPath == prefix || strings.HasPrefix(requestPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// versioned return api whose version is set by Versioner unless annotated
+func (p *Project) versioned(api API) API {
+	if api.Version == "" && p.Versioner != nil {
+		api.Version = p.Versioner(api)
+	}
+	return api
 }
 
 func (p *Project) appendAPI(newAPI API) {
+	newAPI = p.versioned(newAPI)
+	p.unsaved = append(p.unsaved, newAPI)
 	for i, api := range p.APIs {
 		if newAPI.equal(api) {
 			// replace
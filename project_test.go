@@ -1,6 +1,13 @@
 package apidoc
 
-import "testing"
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestAppendAPI(t *testing.T) {
 	p := Project{
@@ -33,3 +40,94 @@ func TestAppendAPI(t *testing.T) {
 		t.Fatal("API len is not 2")
 	}
 }
+
+func TestSaveMergesOtherProcesses(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	documentPath := filepath.Join(dir, "apidoc.html")
+	outputs := []Output{{Format: FormatMarkdown, Path: filepath.Join(dir, "apidoc.md")}}
+
+	p1 := Project{DocumentPath: documentPath, Outputs: outputs, APIs: []API{}}
+	p1.appendAPI(newTestAPI())
+	if err := p1.save(); err != nil {
+		t.Fatal(err)
+	}
+	a2 := newTestAPI()
+	a2.RequestPath = "/items"
+	p2 := Project{DocumentPath: documentPath, Outputs: outputs, APIs: []API{}}
+	p2.appendAPI(a2)
+	if err := p2.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	apis, err := LoadAPIs(p2.getDocumentJSONPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 2 {
+		t.Fatal("API len is not 2")
+	}
+}
+
+func TestSaveKeepsNewerAPIsOfOtherProcesses(t *testing.T) {
+	store := &MemoryStore{}
+	old := newTestAPI()
+	old.ResponseBody = "old"
+	if err := store.Save([]API{old}); err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := Project{Store: store, DisableOutputs: true}
+	if err := p1.load(); err != nil {
+		t.Fatal(err)
+	}
+	p2 := Project{Store: store, DisableOutputs: true}
+	if err := p2.load(); err != nil {
+		t.Fatal(err)
+	}
+	newer := newTestAPI()
+	newer.ResponseBody = "newer"
+	p2.appendAPI(newer)
+	if err := p2.save(); err != nil {
+		t.Fatal(err)
+	}
+	other := newTestAPI()
+	other.RequestPath = "/items"
+	p1.appendAPI(other)
+	if err := p1.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	apis, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 2 {
+		t.Fatal("API len is not 2", len(apis))
+	}
+	for _, api := range apis {
+		if api.RequestPath == newer.RequestPath && api.ResponseBody != "newer" {
+			t.Fatal("stale api overwrote newer one", api.ResponseBody)
+		}
+	}
+}
+
+func TestWriteHTMLSelfContained(t *testing.T) {
+	p := Project{DocumentTitle: "apidoc-test", APIs: []API{newTestAPI()}}
+	var buf bytes.Buffer
+	if err := p.WriteHTML(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, s := range []string{"<link", "src=", "bootstrapcdn", "googleapis"} {
+		if strings.Contains(html, s) {
+			t.Fatalf("%s must not be in default html", s)
+		}
+	}
+	if !strings.Contains(html, ".json-key") || !strings.Contains(html, "getElementById('search')") {
+		t.Fatal("assets are not inlined")
+	}
+}
@@ -0,0 +1,94 @@
+package apidoc
+
+import (
+	"io/ioutil"
+	"net/http"
+)
+
+// replaySuppressedHeaders are added by http server, not by handlers middleware records
+var replaySuppressedHeaders = []string{"Connection", "Content-Length", "Date", "Transfer-Encoding"}
+
+// Replayer send recorded requests to BaseURL and compare responses with recorded responses
+type Replayer struct {
+	BaseURL string
+	// Client is http.DefaultClient if nil
+	Client *http.Client
+	// Normalizers replace volatile values of responses like Project.Normalizers did for recorded apis
+	Normalizers []Normalizer
+}
+
+// ReplayResult has result of a replayed api
+type ReplayResult struct {
+	// Expected is recorded api
+	Expected API
+	// Actual has response from BaseURL
+	Actual API
+	Drifts []Drift
+	Err    error
+}
+
+// OK report whether response is same as recorded response
+func (r ReplayResult) OK() bool {
+	return r.Err == nil && len(r.Drifts) == 0
+}
+
+func (r *Replayer) client() *http.Client {
+	if r.Client != nil {
+		return r.Client
+	}
+	return http.DefaultClient
+}
+
+// Replay send each recorded request and compare response
+func (r *Replayer) Replay(apis []API) []ReplayResult {
+	results := make([]ReplayResult, 0, len(apis))
+	for _, api := range apis {
+		result := ReplayResult{Expected: api}
+		result.Actual, result.Err = r.replay(api)
+		if result.Err == nil {
+			result.Drifts = compareAPI(api, result.Actual)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (r *Replayer) replay(api API) (API, error) {
+	actual := NewAPI()
+	req, err := api.NewRequest(r.BaseURL)
+	if err != nil {
+		return actual, err
+	}
+	if err := actual.ReadRequest(req, true); err != nil {
+		return actual, err
+	}
+	res, err := r.client().Do(req)
+	if err != nil {
+		return actual, err
+	}
+	defer res.Body.Close()
+	actual.MeasureDuration()
+
+	actual.ResponseSuppressedHeaders = make(map[string]bool, len(api.ResponseSuppressedHeaders)+len(replaySuppressedHeaders))
+	for header := range api.ResponseSuppressedHeaders {
+		actual.ResponseSuppressedHeaders[header] = true
+	}
+	for _, header := range replaySuppressedHeaders {
+		actual.ResponseSuppressedHeaders[header] = true
+	}
+	if err := actual.ReadResponseHeader(res.Header); err != nil {
+		return actual, err
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return actual, err
+	}
+	if err := actual.WrapResponseBody(body); err != nil {
+		return actual, err
+	}
+	actual.ResponseStatusCode = res.StatusCode
+	for _, n := range r.Normalizers {
+		actual = n(actual)
+	}
+	return actual, nil
+}
@@ -0,0 +1,59 @@
+package apidoc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "30" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprint(w, `{"users": [{"id": 1, "name": "test1"}]}`)
+	}))
+	defer ts.Close()
+
+	a1 := newTestAPI()
+	a2 := newTestAPI()
+	a2.RequestPath = "/items"
+	a2.ResponseBody = `{"items": []}`
+	r := Replayer{BaseURL: ts.URL}
+	results := r.Replay([]API{a1, a2})
+	if len(results) != 2 {
+		t.Fatal("result len is not 2")
+	}
+	if !results[0].OK() {
+		t.Fatalf("unexpected result %v %v", results[0].Err, results[0].Drifts)
+	}
+	if results[1].OK() {
+		t.Fatal("drift must be found")
+	}
+	if results[1].Drifts[0].Field != "response body $.items" {
+		t.Fatalf("unexpected drifts %v", results[1].Drifts)
+	}
+}
+
+func TestReplayNormalizers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprint(w, `{"users": [{"id": 1, "name": "test1"}]}`)
+	}))
+	defer ts.Close()
+
+	// recorded with NormalizeJSONPath, so id is a string placeholder
+	api := newTestAPI()
+	api.ResponseBody = `{"users": [{"id": "<id>", "name": "test1"}]}`
+	r := Replayer{BaseURL: ts.URL}
+	if results := r.Replay([]API{api}); results[0].OK() {
+		t.Fatal("raw response must drift from normalized api")
+	}
+	r.Normalizers = []Normalizer{NormalizeJSONPath("$.users[].id", "<id>")}
+	if results := r.Replay([]API{api}); !results[0].OK() {
+		t.Fatalf("unexpected result %v %v", results[0].Err, results[0].Drifts)
+	}
+}
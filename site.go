@@ -0,0 +1,149 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// siteSearchEntry is an entry of search-index.json
+type siteSearchEntry struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Group   string `json:"group"`
+	Version string `json:"version,omitempty"`
+	Text    string `json:"text"`
+}
+
+// Anchor return stable id of group for urls, e.g. users
+func (g Group) Anchor() string {
+	if a := anchor(g.Name); a != "" {
+		return a
+	}
+	return "root"
+}
+
+func apiSitePath(anchor string) string {
+	return "endpoints/" + anchor + ".html"
+}
+
+func (g Group) sitePath() string {
+	return "tags/" + g.Anchor() + ".html"
+}
+
+// WriteSite write multi-page static site into dir
+//
+//	index.html              groups of apis
+//	tags/<group>.html       apis of a group, see API.GroupName and Group.Anchor
+//	endpoints/<api>.html    an api, see API.Anchor, colliding ones are numbered like get-users-list-200-2
+//	search-index.json       entries searched from every page
+//
+// Urls are stable as long as version, method, path and status code of apis are, pages of removed apis are deleted.
+func (p *Project) WriteSite(dir string) error {
+	t, err := p.newTemplate("site")
+	if err != nil {
+		return err
+	}
+	if _, err := t.Parse(siteTemplate); err != nil {
+		return err
+	}
+	for _, sub := range []string{"tags", "endpoints"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	groups := p.Groups()
+	anchors := p.anchors()
+	generatedAt := p.generatedAt()
+	written := map[string]bool{}
+	page := func(rel, name string, data map[string]interface{}) error {
+		data["title"] = p.DocumentTitle
+		data["description"] = p.Description
+		data["groups"] = groups
+		data["generatedAt"] = generatedAt
+		data["css"] = template.CSS(assetCSS + assetSiteCSS)
+		data["js"] = template.JS(assetJS + assetSiteJS)
+		data["root"] = strings.Repeat("../", strings.Count(rel, "/"))
+		written[filepath.FromSlash(rel)] = true
+		return writeFileAtomic(filepath.Join(dir, filepath.FromSlash(rel)), func(w io.Writer) error {
+			return t.ExecuteTemplate(w, name, data)
+		})
+	}
+
+	if err := page("index.html", "site-index", map[string]interface{}{}); err != nil {
+		return err
+	}
+	var entries []siteSearchEntry
+	for _, g := range groups {
+		if err := page(g.sitePath(), "site-tag", map[string]interface{}{
+			"page":  g.Name,
+			"group": g,
+			"apis":  g.APIs,
+		}); err != nil {
+			return err
+		}
+		for _, api := range g.APIs {
+			sitePath := apiSitePath(anchors[api.anchorKey()])
+			if err := page(sitePath, "site-endpoint", map[string]interface{}{
+				"page":  api.RequestMethod + " " + api.RequestPath,
+				"group": g,
+				"api":   api,
+			}); err != nil {
+				return err
+			}
+			entries = append(entries, siteSearchEntry{
+				Title:   api.RequestMethod + " " + api.RequestPath + " " + strconv.Itoa(api.ResponseStatusCode),
+				URL:     sitePath,
+				Group:   g.Name,
+				Version: api.Version,
+				Text:    api.RequestBody + " " + api.ResponseBody,
+			})
+		}
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "search-index.json"), func(w io.Writer) error {
+		if entries == nil {
+			entries = []siteSearchEntry{}
+		}
+		return json.NewEncoder(w).Encode(entries)
+	}); err != nil {
+		return err
+	}
+	return removeStalePages(dir, written)
+}
+
+// removeStalePages remove pages of apis and groups which are not written
+func removeStalePages(dir string, written map[string]bool) error {
+	for _, sub := range []string{"tags", "endpoints"} {
+		files, err := ioutil.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			rel := filepath.Join(sub, f.Name())
+			if f.IsDir() || !strings.HasSuffix(f.Name(), ".html") || written[rel] {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, rel)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteSite remove files written by WriteSite, and dir if it is empty then
+func deleteSite(dir string) error {
+	for _, name := range []string{"index.html", "search-index.json", "tags", "endpoints"} {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	os.Remove(dir)
+	return nil
+}
@@ -0,0 +1,116 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	login := newTestAPI()
+	login.RequestMethod = "POST"
+	login.RequestPath = "/login"
+	login.Tags = []string{"auth"}
+	login.ResponseBody = `{"token": "secret"}`
+	p := Project{
+		DocumentTitle: "apidoc-test",
+		Outputs:       []Output{{Format: FormatSite, Path: dir}},
+		APIs:          []API{newTestAPI(), login},
+	}
+	if err := p.writeOutputFiles(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"index.html", "tags/users.html", "tags/auth.html", "endpoints/get-users-200.html", "endpoints/post-login-200.html"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "endpoints", "post-login-200.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`href="../tags/auth.html"`, `href="../endpoints/get-users-200.html"`, `<span class="json-key">`} {
+		if !strings.Contains(string(b), s) {
+			t.Fatalf("%s is not rendered", s)
+		}
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "search-index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []siteSearchEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].URL != "endpoints/post-login-200.html" || !strings.Contains(entries[1].Text, "secret") {
+		t.Fatalf("%+v", entries)
+	}
+
+	// pages of removed apis are deleted
+	p.APIs = p.APIs[:1]
+	if err := p.writeOutputFiles(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"tags/auth.html", "endpoints/post-login-200.html"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Fatalf("%s is not deleted", name)
+		}
+	}
+
+	if err := p.deleteOutputFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatal("site is not deleted")
+	}
+}
+
+func TestWriteSiteCollidingAnchors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	slash := newTestAPI()
+	slash.RequestPath = "/users/list"
+	slash.ResponseBody = `{"from": "slash"}`
+	hyphen := newTestAPI()
+	hyphen.RequestPath = "/users-list"
+	hyphen.ResponseBody = `{"from": "hyphen"}`
+	p := Project{APIs: []API{slash, hyphen}}
+	if err := p.WriteSite(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "search-index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []siteSearchEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].URL == entries[1].URL {
+		t.Fatalf("%+v", entries)
+	}
+	for _, e := range entries {
+		b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(e.URL)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(e.Text, "slash") != strings.Contains(string(b), "slash") {
+			t.Fatalf("%s does not point to page of %s", e.URL, e.Title)
+		}
+	}
+}
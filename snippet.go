@@ -0,0 +1,127 @@
+package apidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// snippetSkippedHeaders are not worth copying into commands
+var snippetSkippedHeaders = map[string]bool{
+	"Accept-Encoding": true,
+	"Content-Length":  true,
+}
+
+func (a API) snippetHeaderKeys() []string {
+	keys := []string{}
+	for _, key := range sortedKeys(a.RequestHeaders) {
+		if !snippetSkippedHeaders[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// requestBodyString return raw request body, post forms are encoded
+func (a API) requestBodyString() string {
+	if len(a.RequestPostForms) == 0 {
+		return a.RequestBody
+	}
+	values := []string{}
+	for _, key := range sortedKeys(a.RequestPostForms) {
+		values = append(values, key+"="+a.RequestPostForms[key])
+	}
+	return strings.Join(values, "&")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// CurlCommand return curl command reproducing request
+func (a API) CurlCommand(baseURL string) string {
+	lines := []string{"curl -X " + a.RequestMethod + " " + shellQuote(a.requestURL(baseURL))}
+	for _, key := range a.snippetHeaderKeys() {
+		lines = append(lines, "-H "+shellQuote(key+": "+headerValue(a.RequestHeaders[key])))
+	}
+	if body := a.requestBodyString(); body != "" {
+		lines = append(lines, "--data-raw "+shellQuote(body))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
+
+// HTTPieCommand return httpie command reproducing request
+func (a API) HTTPieCommand(baseURL string) string {
+	args := []string{"http"}
+	if len(a.RequestPostForms) > 0 {
+		args = append(args, "--form")
+	} else if a.RequestBody != "" {
+		args = append(args, "--raw "+shellQuote(a.RequestBody))
+	}
+	lines := []string{strings.Join(append(args, a.RequestMethod, shellQuote(a.requestURL(baseURL))), " ")}
+	for _, key := range a.snippetHeaderKeys() {
+		if len(a.RequestPostForms) > 0 && key == "Content-Type" {
+			// --form sets it
+			continue
+		}
+		lines = append(lines, shellQuote(key+":"+headerValue(a.RequestHeaders[key])))
+	}
+	for _, key := range sortedKeys(a.RequestPostForms) {
+		lines = append(lines, shellQuote(key+"="+unescapeQuery(a.RequestPostForms[key])))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
+
+func goStringLiteral(s string) string {
+	if strconv.CanBackquote(strings.Replace(s, "\n", "", -1)) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// GoSnippet return go net/http code reproducing request
+func (a API) GoSnippet(baseURL string) string {
+	var b bytes.Buffer
+	body := "nil"
+	if s := a.requestBodyString(); s != "" {
+		fmt.Fprintf(&b, "body := strings.NewReader(%s)\n", goStringLiteral(s))
+		body = "body"
+	}
+	fmt.Fprintf(&b, "req, err := http.NewRequest(%q, %q, %s)\n", a.RequestMethod, a.requestURL(baseURL), body)
+	b.WriteString("if err != nil {\n\tlog.Fatal(err)\n}\n")
+	for _, key := range a.snippetHeaderKeys() {
+		fmt.Fprintf(&b, "req.Header.Set(%q, %q)\n", key, headerValue(a.RequestHeaders[key]))
+	}
+	b.WriteString("res, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("if err != nil {\n\tlog.Fatal(err)\n}\n")
+	b.WriteString("defer res.Body.Close()")
+	return b.String()
+}
+
+func jsStringLiteral(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+	return string(b)
+}
+
+// FetchSnippet return javascript fetch code reproducing request
+func (a API) FetchSnippet(baseURL string) string {
+	options := []string{"  method: " + jsStringLiteral(a.RequestMethod)}
+	if keys := a.snippetHeaderKeys(); len(keys) > 0 {
+		headers := make([]string, 0, len(keys))
+		for _, key := range keys {
+			headers = append(headers, "    "+jsStringLiteral(key)+": "+jsStringLiteral(headerValue(a.RequestHeaders[key])))
+		}
+		options = append(options, "  headers: {\n"+strings.Join(headers, ",\n")+"\n  }")
+	}
+	if body := a.requestBodyString(); body != "" {
+		options = append(options, "  body: "+jsStringLiteral(body))
+	}
+	return "fetch(" + jsStringLiteral(a.requestURL(baseURL)) + ", {\n" + strings.Join(options, ",\n") + "\n})\n" +
+		"  .then(res => res.text())\n" +
+		"  .then(console.log);"
+}
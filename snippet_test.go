@@ -0,0 +1,65 @@
+package apidoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestSnippetAPI() API {
+	api := NewAPI()
+	api.RequestMethod = "PUT"
+	api.RequestPath = "/users"
+	api.RequestHeaders["Content-Type"] = " application/json; charset=utf-8\r"
+	api.RequestHeaders["Accept-Encoding"] = " gzip\r"
+	api.RequestBody = "{\n  \"name\": \"it's me\"\n}"
+	return api
+}
+
+func TestCurlCommand(t *testing.T) {
+	api := newTestSnippetAPI()
+	want := "curl -X PUT 'http://localhost:8080/users' \\\n" +
+		"  -H 'Content-Type: application/json; charset=utf-8' \\\n" +
+		"  --data-raw '{\n  \"name\": \"it'\\''s me\"\n}'"
+	if got := api.CurlCommand("http://localhost:8080/"); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHTTPieCommand(t *testing.T) {
+	api := NewAPI()
+	api.RequestMethod = "POST"
+	api.RequestPath = "/users"
+	api.RequestHeaders["Content-Type"] = "application/x-www-form-urlencoded"
+	api.RequestPostForms["name"] = "goto+katsuya"
+	want := "http --form POST 'http://localhost/users' \\\n  'name=goto katsuya'"
+	if got := api.HTTPieCommand(""); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGoSnippet(t *testing.T) {
+	got := newTestSnippetAPI().GoSnippet("")
+	for _, want := range []string{
+		"body := strings.NewReader(`{\n  \"name\": \"it's me\"\n}`)",
+		`http.NewRequest("PUT", "http://localhost/users", body)`,
+		`req.Header.Set("Content-Type", "application/json; charset=utf-8")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("%q is not contained in\n%s", want, got)
+		}
+	}
+}
+
+func TestFetchSnippet(t *testing.T) {
+	got := newTestSnippetAPI().FetchSnippet("")
+	for _, want := range []string{
+		`fetch("http://localhost/users", {`,
+		`  method: "PUT"`,
+		`    "Content-Type": "application/json; charset=utf-8"`,
+		`  body: "{\n  \"name\": \"it's me\"\n}"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("%q is not contained in\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package apidoc
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SortBy order of apis in documents
+type SortBy int
+
+const (
+	// SortByPath order by path, method, status code and version, default so that documents don't depend on order of tests
+	SortByPath SortBy = iota
+	// SortByTag order by API.GroupName, then by path, method, status code and version
+	SortByTag
+	// SortByFirstSeen keep order in which apis are recorded first
+	SortByFirstSeen
+)
+
+// ParseSortBy parse first-seen, path or tag
+func ParseSortBy(s string) (SortBy, error) {
+	switch s {
+	case "first-seen":
+		return SortByFirstSeen, nil
+	case "path":
+		return SortByPath, nil
+	case "tag":
+		return SortByTag, nil
+	}
+	return 0, fmt.Errorf("apidoc: unknown sort %q", s)
+}
+
+// SortAPIs sort apis stably in order of by
+func SortAPIs(apis []API, by SortBy) {
+	switch by {
+	case SortByPath:
+		sort.SliceStable(apis, func(i, j int) bool {
+			return lessByPath(apis[i], apis[j])
+		})
+	case SortByTag:
+		sort.SliceStable(apis, func(i, j int) bool {
+			if gi, gj := apis[i].GroupName(), apis[j].GroupName(); gi != gj {
+				return gi < gj
+			}
+			return lessByPath(apis[i], apis[j])
+		})
+	}
+}
+
+func lessByPath(a, b API) bool {
+	if a.RequestPath != b.RequestPath {
+		return a.RequestPath < b.RequestPath
+	}
+	if a.RequestMethod != b.RequestMethod {
+		return a.RequestMethod < b.RequestMethod
+	}
+	if a.ResponseStatusCode != b.ResponseStatusCode {
+		return a.ResponseStatusCode < b.ResponseStatusCode
+	}
+	return a.Version < b.Version
+}
+
+// generatedAt return SOURCE_DATE_EPOCH, zero if not set,
+// so that same apis render byte-identical documents
+func (p *Project) generatedAt() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Time{}
+}
@@ -0,0 +1,191 @@
+package apidoc
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func newSortTestAPIs() []API {
+	var apis []API
+	for _, a := range []struct {
+		method string
+		path   string
+		status int
+		tag    string
+	}{
+		{"POST", "/users", 201, ""},
+		{"GET", "/users", 404, ""},
+		{"GET", "/login", 200, "auth"},
+		{"GET", "/users", 200, ""},
+		{"DELETE", "/items", 204, ""},
+	} {
+		api := newTestAPI()
+		api.RequestMethod = a.method
+		api.RequestPath = a.path
+		api.ResponseStatusCode = a.status
+		if a.tag != "" {
+			api.Tags = []string{a.tag}
+		}
+		apis = append(apis, api)
+	}
+	return apis
+}
+
+func anchors(apis []API) []string {
+	var s []string
+	for _, api := range apis {
+		s = append(s, api.Anchor())
+	}
+	return s
+}
+
+func TestSortAPIs(t *testing.T) {
+	for _, tt := range []struct {
+		by   SortBy
+		want []string
+	}{
+		{SortByFirstSeen, []string{"post-users-201", "get-users-404", "get-login-200", "get-users-200", "delete-items-204"}},
+		{SortByPath, []string{"delete-items-204", "get-login-200", "get-users-200", "get-users-404", "post-users-201"}},
+		{SortByTag, []string{"get-login-200", "delete-items-204", "get-users-200", "get-users-404", "post-users-201"}},
+	} {
+		apis := newSortTestAPIs()
+		SortAPIs(apis, tt.by)
+		if got := anchors(apis); !equalStrings(got, tt.want) {
+			t.Fatalf("%d: got %v, want %v", tt.by, got, tt.want)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseSortBy(t *testing.T) {
+	if by, err := ParseSortBy("tag"); err != nil || by != SortByTag {
+		t.Fatal(by, err)
+	}
+	if _, err := ParseSortBy("random"); err == nil {
+		t.Fatal("unknown sort must be error")
+	}
+}
+
+func TestRenderIsDeterministic(t *testing.T) {
+	render := func(apis []API) map[Format][]byte {
+		p := Project{DocumentTitle: "apidoc-test", SortBy: SortByPath, Store: &MemoryStore{}, DisableOutputs: true, APIs: []API{}}
+		for _, api := range apis {
+			p.appendAPI(api)
+			if err := p.save(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		out := map[Format][]byte{}
+		for f := range renderers {
+			var buf bytes.Buffer
+			if err := p.Render(&buf, Output{Format: f}); err != nil {
+				t.Fatal(err)
+			}
+			out[f] = buf.Bytes()
+		}
+		return out
+	}
+	apis := newSortTestAPIs()
+	started := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := range apis {
+		apis[i].RequestHeaders["Accept"] = " */*\r"
+		apis[i].RequestHeaders["X-Client"] = " test\r"
+		apis[i].RequestStartedAt = started.Add(time.Duration(i) * time.Second)
+	}
+	reversed := make([]API, len(apis))
+	for i, api := range apis {
+		reversed[len(apis)-1-i] = api
+	}
+
+	first, second := render(apis), render(reversed)
+	for f, b := range first {
+		if !bytes.Equal(b, second[f]) {
+			t.Fatalf("%s output differs by recording order", f)
+		}
+	}
+	if bytes.Contains(first[FormatHTML], []byte("Generated at")) {
+		t.Fatal("generated time must not be rendered without SOURCE_DATE_EPOCH")
+	}
+
+	os.Setenv("SOURCE_DATE_EPOCH", "0")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+	if !bytes.Contains(render(apis)[FormatHTML], []byte("Generated at 1970-01-01 00:00:00 UTC")) {
+		t.Fatal("SOURCE_DATE_EPOCH is not used")
+	}
+}
+
+func TestRecordTwiceIsByteIdentical(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	})
+	record := func(paths ...string) map[Format][]byte {
+		p = Project{
+			DocumentTitle: "apidoc-test",
+			Store:         &MemoryStore{},
+			Outputs:       []Output{},
+			Normalizers:   []Normalizer{NormalizeTiming(), NormalizeRegexp(`^127\.0\.0\.1:\d+$`, "localhost:8080")},
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			api := NewAPI()
+			if err := api.ReadRequest(r, true); err != nil {
+				t.Error(err)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, r)
+			api.MeasureDuration()
+			api.ReadResponseHeader(recorder.Header())
+			api.WrapResponseBody(recorder.Body.Bytes())
+			api.ResponseStatusCode = recorder.Code
+			if err := Gen(api); err != nil {
+				t.Error(err)
+			}
+		}))
+		defer ts.Close()
+		for _, path := range paths {
+			res, err := http.Get(ts.URL + path + "?limit=30")
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+		}
+		out := map[Format][]byte{}
+		for f := range renderers {
+			var buf bytes.Buffer
+			if err := snapshot().Render(&buf, Output{Format: f}); err != nil {
+				t.Fatal(err)
+			}
+			out[f] = buf.Bytes()
+		}
+		return out
+	}
+
+	first := record("/users", "/items", "/login")
+	second := record("/login", "/users", "/items")
+	for f, b := range first {
+		if !bytes.Equal(b, second[f]) {
+			t.Fatalf("%s output differs between runs\n%s\n%s", f, b, second[f])
+		}
+	}
+}
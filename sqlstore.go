@@ -0,0 +1,223 @@
+package apidoc
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const sqlStoreSchema = `CREATE TABLE IF NOT EXISTS apidoc_apis (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	version TEXT NOT NULL,
+	method TEXT NOT NULL,
+	path TEXT NOT NULL,
+	status_code INTEGER NOT NULL,
+	revision TEXT NOT NULL,
+	test_name TEXT NOT NULL,
+	recorded_at TEXT NOT NULL,
+	api TEXT NOT NULL
+)`
+
+// sqlStoreRevisionsSchema record position of each revision in history, even if it changed no apis
+const sqlStoreRevisionsSchema = `CREATE TABLE IF NOT EXISTS apidoc_revisions (
+	revision TEXT PRIMARY KEY,
+	api_id INTEGER NOT NULL,
+	saved_at TEXT NOT NULL
+)`
+
+// SQLStore store every changed api with history in SQLite database
+// Open db with a SQLite driver, e.g. github.com/mattn/go-sqlite3, and pass it to NewSQLStore.
+type SQLStore struct {
+	DB *sql.DB
+	// Revision is recorded with apis, e.g. GitRevision()
+	Revision string
+	// TestName is recorded with apis, set it like store.TestName = t.Name()
+	TestName string
+}
+
+// HistoryEntry has an api recorded in SQLStore
+type HistoryEntry struct {
+	Revision   string
+	TestName   string
+	RecordedAt time.Time
+	API        API
+}
+
+// sqlQuerier is *sql.DB or *sql.Tx
+type sqlQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// NewSQLStore new sql store instance, create tables if not exist
+func NewSQLStore(db *sql.DB, revision string) (*SQLStore, error) {
+	for _, schema := range []string{sqlStoreSchema, sqlStoreRevisionsSchema} {
+		if _, err := db.Exec(schema); err != nil {
+			return nil, err
+		}
+	}
+	return &SQLStore{DB: db, Revision: revision}, nil
+}
+
+// GitRevision return commit hash of HEAD, empty if git is not available
+func GitRevision() string {
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Load return latest apis
+func (s *SQLStore) Load() ([]API, error) {
+	apis, err := s.latest(s.DB, -1)
+	if err != nil || len(apis) == 0 {
+		return nil, err
+	}
+	return apis, nil
+}
+
+// AsOf return latest apis recorded until last save of revision, empty if revision is never saved
+func (s *SQLStore) AsOf(revision string) ([]API, error) {
+	var maxID int64
+	err := s.DB.QueryRow(`SELECT api_id FROM apidoc_revisions WHERE revision = ?`, revision).Scan(&maxID)
+	if err == sql.ErrNoRows {
+		return []API{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.latest(s.DB, maxID)
+}
+
+// latest return latest api for each version, method, path and status code, until maxID if not negative
+func (s *SQLStore) latest(q sqlQuerier, maxID int64) ([]API, error) {
+	rows, err := q.Query(`SELECT api FROM apidoc_apis WHERE id IN (
+		SELECT MAX(id) FROM apidoc_apis WHERE ? < 0 OR id <= ? GROUP BY version, method, path, status_code
+	) ORDER BY id`, maxID, maxID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	apis := []API{}
+	for rows.Next() {
+		var b string
+		if err := rows.Scan(&b); err != nil {
+			return nil, err
+		}
+		var api API
+		if err := json.Unmarshal([]byte(b), &api); err != nil {
+			return nil, err
+		}
+		apis = append(apis, api)
+	}
+	return apis, rows.Err()
+}
+
+// Save insert apis which differ from latest recorded ones
+func (s *SQLStore) Save(apis []API) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	latest, err := s.latest(tx, -1)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	recordedAt := time.Now().UTC().Format(time.RFC3339Nano)
+	for _, api := range apis {
+		if i := indexOfAPI(latest, api); i >= 0 && sameExchange(latest[i], api) {
+			continue
+		}
+		b, err := json.Marshal(api)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO apidoc_apis (version, method, path, status_code, revision, test_name, recorded_at, api) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			api.Version, api.RequestMethod, api.RequestPath, api.ResponseStatusCode, s.Revision, s.TestName, recordedAt, string(b)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := s.saveRevision(tx, recordedAt); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveRevision move position of Revision to the last recorded api
+func (s *SQLStore) saveRevision(tx *sql.Tx, savedAt string) error {
+	var maxID int64
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM apidoc_apis`).Scan(&maxID); err != nil {
+		return err
+	}
+	result, err := tx.Exec(`UPDATE apidoc_revisions SET api_id = ?, saved_at = ? WHERE revision = ?`, maxID, savedAt, s.Revision)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO apidoc_revisions (revision, api_id, saved_at) VALUES (?, ?, ?)`, s.Revision, maxID, savedAt)
+	return err
+}
+
+// Delete remove all recorded apis and history of every revision
+func (s *SQLStore) Delete() error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{"apidoc_apis", "apidoc_revisions"} {
+		if _, err := tx.Exec(`DELETE FROM ` + table); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// History return every recorded api of version, method and path in recorded order
+func (s *SQLStore) History(version, method, path string) ([]HistoryEntry, error) {
+	rows, err := s.DB.Query(`SELECT revision, test_name, recorded_at, api FROM apidoc_apis WHERE version = ? AND method = ? AND path = ? ORDER BY id`, version, method, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var recordedAt, b string
+		if err := rows.Scan(&e.Revision, &e.TestName, &recordedAt, &b); err != nil {
+			return nil, err
+		}
+		if e.RecordedAt, err = time.Parse(time.RFC3339Nano, recordedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(b), &e.API); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// FirstSeen return first recorded api of version, method and path whose response body has field like $.users[].id
+func (s *SQLStore) FirstSeen(version, method, path, field string) (*HistoryEntry, error) {
+	entries, err := s.History(version, method, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if shape, ok := jsonShape(e.API.ResponseBody); ok {
+			if _, ok := shape[field]; ok {
+				return &e, nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+//go:build sqlite
+// +build sqlite
+
+// Run with go test -tags sqlite, which needs github.com/mattn/go-sqlite3 and cgo.
+
+package apidoc
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestSQLStore(t *testing.T, revision string) *SQLStore {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection opens another in-memory database
+	db.SetMaxOpenConns(1)
+	s, err := NewSQLStore(db, revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSQLStore(t *testing.T) {
+	s := newTestSQLStore(t, "r1")
+	defer s.DB.Close()
+
+	if apis, err := s.Load(); err != nil || apis != nil {
+		t.Fatal("empty store must load nil", apis, err)
+	}
+	a1 := newTestAPI()
+	if err := s.Save([]API{a1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// r2 changes nothing, r3 changes response
+	s.Revision = "r2"
+	if err := s.Save([]API{a1}); err != nil {
+		t.Fatal(err)
+	}
+	s.Revision = "r3"
+	s.TestName = "TestUsers"
+	a2 := newTestAPI()
+	a2.ResponseBody = `{"users": [{"id": 1, "name": "test1", "email": "a@example.com"}]}`
+	if err := s.Save([]API{a2}); err != nil {
+		t.Fatal(err)
+	}
+
+	apis, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 1 || apis[0].ResponseBody != a2.ResponseBody {
+		t.Fatalf("latest api must be loaded %+v", apis)
+	}
+	for revision, want := range map[string]string{"r1": a1.ResponseBody, "r2": a1.ResponseBody, "r3": a2.ResponseBody} {
+		apis, err := s.AsOf(revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(apis) != 1 || apis[0].ResponseBody != want {
+			t.Fatalf("%s: %+v", revision, apis)
+		}
+	}
+	if apis, err := s.AsOf("unknown"); err != nil || len(apis) != 0 {
+		t.Fatal(apis, err)
+	}
+
+	entries, err := s.History("", "GET", "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Revision != "r1" || entries[1].Revision != "r3" || entries[1].TestName != "TestUsers" || entries[1].RecordedAt.IsZero() {
+		t.Fatalf("%+v", entries)
+	}
+	e, err := s.FirstSeen("", "GET", "/users", "$.users[].email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil || e.Revision != "r3" {
+		t.Fatalf("%+v", e)
+	}
+
+	if err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if apis, err := s.Load(); err != nil || apis != nil {
+		t.Fatal("apis of every revision must be deleted", apis, err)
+	}
+	if apis, err := s.AsOf("r1"); err != nil || len(apis) != 0 {
+		t.Fatal("history must be deleted", apis, err)
+	}
+}
+
+func TestSQLStoreVersions(t *testing.T) {
+	s := newTestSQLStore(t, "r1")
+	defer s.DB.Close()
+	v1 := newTestAPI()
+	v1.Version = "v1"
+	v2 := newTestAPI()
+	v2.Version = "v2"
+	if err := s.Save([]API{v1, v2}); err != nil {
+		t.Fatal(err)
+	}
+	apis, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 2 || apis[0].Version != "v1" || apis[1].Version != "v2" {
+		t.Fatalf("each version must be loaded %+v", apis)
+	}
+
+	v2.ResponseBody = `{"users": [{"id": 1, "email": "a@example.com"}]}`
+	if err := s.Save([]API{v1, v2}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := s.History("v1", "GET", "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].API.Version != "v1" {
+		t.Fatalf("history of other version must not be returned %+v", entries)
+	}
+	if e, err := s.FirstSeen("v1", "GET", "/users", "$.users[].email"); err != nil || e != nil {
+		t.Fatal("field of other version must not be seen", e, err)
+	}
+	if e, err := s.FirstSeen("v2", "GET", "/users", "$.users[].email"); err != nil || e == nil {
+		t.Fatal("field of v2 must be seen", e, err)
+	}
+}
@@ -0,0 +1,169 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store persist apis
+type Store interface {
+	// Load return nil apis without error if nothing is stored yet
+	Load() ([]API, error)
+	Save(apis []API) error
+	Delete() error
+}
+
+// Locker is implemented by stores shared between processes
+type Locker interface {
+	// Lock block until lock is taken, call returned func to unlock
+	Lock() (func() error, error)
+}
+
+// FileStore store apis in json file
+type FileStore struct {
+	Path string
+	// RecoverCorrupt move corrupt file aside and load no apis
+	RecoverCorrupt bool
+}
+
+// DocumentError is returned when document json file can not be loaded
+type DocumentError struct {
+	Path string
+	// Corrupt is true if file exists but is not valid apis json
+	Corrupt bool
+	// BackupPath is where corrupt file is moved to if RecoverCorrupt is true
+	BackupPath string
+	Err        error
+}
+
+func (e *DocumentError) Error() string {
+	if e.Corrupt {
+		return "apidoc: corrupt document json file " + e.Path + ": " + e.Err.Error()
+	}
+	return "apidoc: load document json file " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap return underlying error
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// Load return nil apis without error if file does not exist or is empty
+func (s *FileStore) Load() ([]API, error) {
+	filePath, err := filepath.Abs(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &DocumentError{Path: filePath, Err: err}
+	}
+	defer file.Close()
+
+	apis := []API{}
+	err = json.NewDecoder(file).Decode(&apis)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err == nil {
+		return apis, nil
+	}
+
+	docErr := &DocumentError{Path: filePath, Corrupt: true, Err: err}
+	if !s.RecoverCorrupt {
+		return nil, docErr
+	}
+	// reserve unique backup name, corrupt files may be recovered many times in a second
+	backup, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".corrupt-")
+	if err != nil {
+		return nil, &DocumentError{Path: filePath, Corrupt: true, Err: err}
+	}
+	backup.Close()
+	docErr.BackupPath = backup.Name()
+	if err := os.Rename(filePath, docErr.BackupPath); err != nil {
+		return nil, &DocumentError{Path: filePath, Corrupt: true, Err: err}
+	}
+	log.Printf("%v, moved to %s", docErr, docErr.BackupPath)
+	return nil, nil
+}
+
+// Save write apis json atomically
+func (s *FileStore) Save(apis []API) error {
+	p := Project{APIs: apis}
+	return writeFileAtomic(s.Path, p.WriteJSON)
+}
+
+// Delete remove file
+func (s *FileStore) Delete() error {
+	filePath, err := filepath.Abs(s.Path)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filePath)
+}
+
+// Lock take advisory lock on Path + ".lock"
+func (s *FileStore) Lock() (func() error, error) {
+	return lockFile(s.Path)
+}
+
+// MemoryStore store apis in memory
+type MemoryStore struct {
+	mu   sync.Mutex
+	apis []API
+}
+
+// Load return copy of saved apis
+func (s *MemoryStore) Load() ([]API, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.apis == nil {
+		return nil, nil
+	}
+	return append([]API{}, s.apis...), nil
+}
+
+// Save keep copy of apis
+func (s *MemoryStore) Save(apis []API) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apis = append([]API{}, apis...)
+	return nil
+}
+
+// Delete forget apis
+func (s *MemoryStore) Delete() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apis = nil
+	return nil
+}
+
+// WriterStore write apis json to W on each save, e.g. to push documents elsewhere
+type WriterStore struct {
+	W io.Writer
+}
+
+// Load return nil, WriterStore can not read
+func (s *WriterStore) Load() ([]API, error) {
+	return nil, nil
+}
+
+// Save write apis json to W
+func (s *WriterStore) Save(apis []API) error {
+	p := Project{APIs: apis}
+	return p.WriteJSON(s.W)
+}
+
+// Delete do nothing
+func (s *WriterStore) Delete() error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package apidoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := &FileStore{Path: filepath.Join(dir, "apidoc.json")}
+	apis, err := s.Load()
+	if err != nil || apis != nil {
+		t.Fatalf("unexpected load %v %v", apis, err)
+	}
+	if err := s.Save([]API{newTestAPI()}); err != nil {
+		t.Fatal(err)
+	}
+	if apis, err = s.Load(); err != nil || len(apis) != 1 {
+		t.Fatalf("unexpected load %v %v", apis, err)
+	}
+	if err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Path); !os.IsNotExist(err) {
+		t.Fatal("file is not deleted")
+	}
+}
+
+func TestFileStoreRecoverCorruptTwice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := &FileStore{Path: filepath.Join(dir, "apidoc.json"), RecoverCorrupt: true}
+	for i := 0; i < 2; i++ {
+		if err := ioutil.WriteFile(s.Path, []byte("[{"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if apis, err := s.Load(); err != nil || apis != nil {
+			t.Fatalf("unexpected load %v %v", apis, err)
+		}
+	}
+	backups, err := filepath.Glob(s.Path + ".corrupt-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatal("corrupt file backup is overwritten", backups)
+	}
+}
+
+func TestMemoryStoreWithGen(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+	}()
+	s := &MemoryStore{}
+	if err := Init(Project{Store: s}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Gen(newTestAPI()); err != nil {
+		t.Fatal(err)
+	}
+	apis, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 1 || apis[0].RequestPath != "/users" {
+		t.Fatalf("unexpected apis %v", apis)
+	}
+	if err := Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if apis, _ = s.Load(); apis != nil {
+		t.Fatal("apis are not deleted")
+	}
+}
+
+func TestWriterStore(t *testing.T) {
+	var b bytes.Buffer
+	s := &WriterStore{W: &b}
+	if err := s.Save([]API{newTestAPI()}); err != nil {
+		t.Fatal(err)
+	}
+	apis, err := ReadAPIs(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 1 {
+		t.Fatal("API len is not 1")
+	}
+}
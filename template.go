@@ -0,0 +1,268 @@
+package apidoc
+
+import (
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TemplateFuncs return functions available in html templates
+//
+//	statusText 404          -> "Not Found"
+//	methodColor "GET"       -> css color of method badge like "#2f80ed"
+//	markdown .description   -> html rendered from markdown
+//	highlightJSON .Body     -> html of json with spans of json-key, json-string, json-number and json-literal class
+//	sort "path" .apis       -> apis sorted by path, method, status, version or duration
+//	anchor .Name            -> id usable in url fragment like "user-accounts"
+//	baseURL                 -> Project.BaseURL
+//	apiAnchor .             -> id of api unique in document like "get-users-200", use it rather than API.Anchor
+//
+// Project.Funcs add or override functions.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"statusText":    http.StatusText,
+		"methodColor":   methodColor,
+		"markdown":      markdown,
+		"highlightJSON": highlightJSON,
+		"sort":          sortAPIsByKey,
+		"anchor":        anchor,
+	}
+}
+
+// newTemplate new template with TemplateFuncs, Funcs and "endpoint" template which renders an api
+func (p *Project) newTemplate(name string) (*template.Template, error) {
+	funcs := TemplateFuncs()
+	funcs["baseURL"] = func() string { return p.BaseURL }
+	anchors := p.anchors()
+	funcs["apiAnchor"] = func(a API) string {
+		if id, ok := anchors[a.anchorKey()]; ok {
+			return id
+		}
+		return a.Anchor()
+	}
+	for key, f := range p.Funcs {
+		funcs[key] = f
+	}
+	return template.New(name).Funcs(funcs).Parse(endpointTemplate)
+}
+
+// parseTemplate parse template file, or embedded default template if templatePath is empty
+func (p *Project) parseTemplate(templatePath string) (*template.Template, error) {
+	if templatePath == "" {
+		t, err := p.newTemplate("default.tpl.html")
+		if err != nil {
+			return nil, err
+		}
+		return t.Parse(defaultTemplate)
+	}
+	t, err := p.newTemplate(filepath.Base(templatePath))
+	if err != nil {
+		return nil, err
+	}
+	return t.ParseFiles(templatePath)
+}
+
+// templateData return data passed to html templates, see WriteHTML
+func (p *Project) templateData() map[string]interface{} {
+	return map[string]interface{}{
+		"title":       p.DocumentTitle,
+		"description": p.Description,
+		"baseURL":     p.BaseURL,
+		"project":     p,
+		"apis":        p.APIs,
+		"groups":      p.Groups(),
+		"versions":    p.Versions(),
+		"version":     p.version,
+		"generatedAt": p.generatedAt(),
+		"css":         template.CSS(assetCSS),
+		"js":          template.JS(assetJS),
+	}
+}
+
+var methodColors = map[string]string{
+	http.MethodGet:    "#2f80ed",
+	http.MethodPost:   "#27ae60",
+	http.MethodPut:    "#f2994a",
+	http.MethodPatch:  "#9b51e0",
+	http.MethodDelete: "#eb5757",
+}
+
+func methodColor(method string) string {
+	if c, ok := methodColors[strings.ToUpper(method)]; ok {
+		return c
+	}
+	return "#777777"
+}
+
+func anchor(s string) string {
+	return strings.Trim(anchorPattern.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+func sortAPIsByKey(key string, apis []API) []API {
+	sorted := make([]API, len(apis))
+	copy(sorted, apis)
+	var less func(a, b API) bool
+	switch key {
+	case "method":
+		less = func(a, b API) bool { return a.RequestMethod < b.RequestMethod }
+	case "status":
+		less = func(a, b API) bool { return a.ResponseStatusCode < b.ResponseStatusCode }
+	case "version":
+		less = func(a, b API) bool { return a.Version < b.Version }
+	case "duration":
+		less = func(a, b API) bool { return a.Duration < b.Duration }
+	default:
+		less = func(a, b API) bool { return a.RequestPath < b.RequestPath }
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	return sorted
+}
+
+// highlightJSON wrap tokens of json with span, text which is not json is escaped only
+func highlightJSON(s string) template.HTML {
+	if !isJSON(s) {
+		return template.HTML(template.HTMLEscapeString(s))
+	}
+	var b strings.Builder
+	span := func(class, token string) {
+		b.WriteString(`<span class="` + class + `">` + template.HTMLEscapeString(token) + `</span>`)
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(s) {
+				j++
+			}
+			class := "json-string"
+			if strings.HasPrefix(strings.TrimLeft(s[j:], " \t\r\n"), ":") {
+				class = "json-key"
+			}
+			span(class, s[i:j])
+			i = j
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(s) && strings.IndexByte("0123456789.eE+-", s[j]) >= 0 {
+				j++
+			}
+			span("json-number", s[i:j])
+			i = j
+		case c >= 'a' && c <= 'z':
+			j := i + 1
+			for j < len(s) && s[j] >= 'a' && s[j] <= 'z' {
+				j++
+			}
+			span("json-literal", s[i:j])
+			i = j
+		default:
+			b.WriteString(template.HTMLEscapeString(string(c)))
+			i++
+		}
+	}
+	return template.HTML(b.String())
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownItem    = regexp.MustCompile(`^[-*]\s+(.*)$`)
+	markdownCode    = regexp.MustCompile("`([^`]+)`")
+	markdownBold    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownItalic  = regexp.MustCompile(`\*([^*]+)\*`)
+	markdownLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// markdown render subset of markdown: headings, lists, fenced code, paragraphs, code, bold, italic and links
+func markdown(s string) template.HTML {
+	var b strings.Builder
+	var paragraph []string
+	inList, inCode := false, false
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + markdownInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+		if inList {
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+	for _, line := range strings.Split(strings.Replace(s, "\r\n", "\n", -1), "\n") {
+		if inCode {
+			if strings.HasPrefix(line, "```") {
+				b.WriteString("</code></pre>\n")
+				inCode = false
+			} else {
+				b.WriteString(template.HTMLEscapeString(line) + "\n")
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			b.WriteString("<pre><code>")
+			inCode = true
+		case trimmed == "":
+			flush()
+		case markdownHeading.MatchString(trimmed):
+			flush()
+			m := markdownHeading.FindStringSubmatch(trimmed)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">" + markdownInline(m[2]) + "</h" + level + ">\n")
+		case markdownItem.MatchString(trimmed):
+			if len(paragraph) > 0 {
+				flush()
+			}
+			if !inList {
+				b.WriteString("<ul>\n")
+				inList = true
+			}
+			b.WriteString("<li>" + markdownInline(markdownItem.FindStringSubmatch(trimmed)[1]) + "</li>\n")
+		default:
+			if inList {
+				flush()
+			}
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	if inCode {
+		b.WriteString("</code></pre>\n")
+	}
+	flush()
+	return template.HTML(b.String())
+}
+
+func markdownInline(s string) string {
+	s = template.HTMLEscapeString(s)
+	s = markdownCode.ReplaceAllString(s, "<code>$1</code>")
+	s = markdownBold.ReplaceAllString(s, "<strong>$1</strong>")
+	s = markdownItalic.ReplaceAllString(s, "<em>$1</em>")
+	return markdownLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := markdownLink.FindStringSubmatch(m)
+		if !isSafeURL(sub[2]) {
+			return sub[1]
+		}
+		return `<a href="` + sub[2] + `">` + sub[1] + `</a>`
+	})
+}
+
+func isSafeURL(s string) bool {
+	u, err := url.Parse(html.UnescapeString(s))
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" || u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "mailto"
+}
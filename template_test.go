@@ -0,0 +1,105 @@
+package apidoc
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHighlightJSON(t *testing.T) {
+	got := string(highlightJSON(`{"id": 1, "name": "a<b", "ok": true, "next": null}`))
+	for _, s := range []string{
+		`<span class="json-key">&#34;id&#34;</span>`,
+		`<span class="json-number">1</span>`,
+		`<span class="json-string">&#34;a&lt;b&#34;</span>`,
+		`<span class="json-literal">true</span>`,
+		`<span class="json-literal">null</span>`,
+	} {
+		if !strings.Contains(got, s) {
+			t.Fatalf("%s is not in %s", s, got)
+		}
+	}
+	if got := string(highlightJSON("<b>not json</b>")); got != "&lt;b&gt;not json&lt;/b&gt;" {
+		t.Fatal(got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	got := string(markdown("# Users API\n\nList **all** users with `GET`.\nSee [docs](https://example.com) or [x](javascript:void).\n\n- one\n- *two*\n\n```\n<tag>\n```"))
+	want := "<h1>Users API</h1>\n" +
+		"<p>List <strong>all</strong> users with <code>GET</code>. See <a href=\"https://example.com\">docs</a> or x.</p>\n" +
+		"<ul>\n<li>one</li>\n<li><em>two</em></li>\n</ul>\n" +
+		"<pre><code>&lt;tag&gt;\n</code></pre>\n"
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSortAPIsByKey(t *testing.T) {
+	a := newTestAPI()
+	a.RequestPath = "/b"
+	a.ResponseStatusCode = 200
+	b := newTestAPI()
+	b.RequestPath = "/a"
+	b.ResponseStatusCode = 404
+	apis := []API{a, b}
+	if got := sortAPIsByKey("path", apis); got[0].RequestPath != "/a" {
+		t.Fatal(got)
+	}
+	if got := sortAPIsByKey("status", apis); got[0].ResponseStatusCode != 200 {
+		t.Fatal(got)
+	}
+	if apis[0].RequestPath != "/b" {
+		t.Fatal("apis must not be sorted in place")
+	}
+}
+
+func TestWriteHTMLCustomTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	templatePath := filepath.Join(dir, "custom.tpl.html")
+	tpl := `{{ .title }}|{{ .version }}|{{ range sort "path" .apis }}{{ .RequestMethod }} {{ methodColor .RequestMethod }} {{ statusText .ResponseStatusCode }} {{ anchor .RequestPath }}|{{ end }}{{ shout .title }}`
+	if err := ioutil.WriteFile(templatePath, []byte(tpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	api := newTestAPI()
+	api.Version = "v1"
+	p := Project{
+		DocumentTitle: "apidoc-test",
+		Funcs:         template.FuncMap{"shout": strings.ToUpper},
+		APIs:          []API{api},
+	}
+	var buf bytes.Buffer
+	if err := p.ForVersion("v1").WriteHTML(&buf, templatePath); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "apidoc-test v1|v1|GET #2f80ed OK users|APIDOC-TEST V1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestWriteHTMLExamples(t *testing.T) {
+	api := newTestAPI()
+	example := newTestAPI()
+	example.ResponseBody = `{"example": 2}`
+	api.Examples = []API{example}
+	p := Project{APIs: []API{api}}
+	var buf bytes.Buffer
+	if err := p.WriteHTML(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `<details class="example">`) || !strings.Contains(out, `<span class="json-key">&#34;example&#34;</span>`) {
+		t.Fatal(out)
+	}
+	if strings.Count(out, `id="get-users-200"`) != 1 {
+		t.Fatal("example must not add endpoint")
+	}
+}
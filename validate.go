@@ -0,0 +1,56 @@
+package apidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError has a problem of recorded api
+type ValidationError struct {
+	Index   int
+	Method  string
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("apis[%d] %s %s: %s", e.Index, e.Method, e.Path, e.Message)
+}
+
+// Validate check recorded apis are well formed
+func Validate(apis []API) []ValidationError {
+	var errs []ValidationError
+	seen := map[string]int{}
+	for i, api := range apis {
+		add := func(format string, args ...interface{}) {
+			errs = append(errs, ValidationError{
+				Index:   i,
+				Method:  api.RequestMethod,
+				Path:    api.RequestPath,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+		if api.RequestMethod == "" {
+			add("request method is empty")
+		}
+		if !strings.HasPrefix(api.RequestPath, "/") {
+			add("request path must start with /")
+		}
+		if api.ResponseStatusCode < 100 || api.ResponseStatusCode > 599 {
+			add("response status code %d is invalid", api.ResponseStatusCode)
+		}
+		if api.RequestBody != "" && api.requestContentType() == "application/json" && !isJSON(api.RequestBody) {
+			add("request body is not valid json")
+		}
+		if api.ResponseBody != "" && api.responseContentType() == "application/json" && !isJSON(api.ResponseBody) {
+			add("response body is not valid json")
+		}
+		key := fmt.Sprintf("%s %s %s %d", api.Version, api.RequestMethod, api.RequestPath, api.ResponseStatusCode)
+		if j, ok := seen[key]; ok {
+			add("duplicated with apis[%d]", j)
+		} else {
+			seen[key] = i
+		}
+	}
+	return errs
+}
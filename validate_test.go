@@ -0,0 +1,30 @@
+package apidoc
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	if errs := Validate([]API{newTestAPI()}); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+
+	a1 := newTestAPI()
+	a1.ResponseBody = "{"
+	a2 := NewAPI()
+	a2.RequestPath = "users"
+	errs := Validate([]API{a1, newTestAPI(), a2})
+	want := []string{
+		"apis[0] GET /users: response body is not valid json",
+		"apis[1] GET /users: duplicated with apis[0]",
+		"apis[2]  users: request method is empty",
+		"apis[2]  users: request path must start with /",
+		"apis[2]  users: response status code 0 is invalid",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Fatalf("got %q, want %q", err.Error(), want[i])
+		}
+	}
+}
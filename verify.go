@@ -0,0 +1,172 @@
+package apidoc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// compare apis with document json file instead of writing it if true
+	verification bool
+
+	// drifts found by Gen in verification mode
+	drifts []Drift
+)
+
+// EnableVerification compare apis with document json file instead of writing it
+func EnableVerification() {
+	verification = true
+}
+
+// DisableVerification write document files by Gen
+func DisableVerification() {
+	verification = false
+}
+
+// IsVerifying ref verification
+func IsVerifying() bool {
+	return verification
+}
+
+// Drift has a difference between recorded api and live api
+type Drift struct {
+	Method   string
+	Path     string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s %s: %s expected %q but got %q", d.Method, d.Path, d.Field, d.Expected, d.Actual)
+}
+
+// DriftError is returned by Gen when live api differs from recorded api
+type DriftError struct {
+	Drifts []Drift
+}
+
+func (e *DriftError) Error() string {
+	lines := make([]string, 0, len(e.Drifts))
+	for _, d := range e.Drifts {
+		lines = append(lines, d.String())
+	}
+	return "apidoc: api drifted from document\n" + strings.Join(lines, "\n")
+}
+
+// TestingT is implemented by *testing.T
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Drifts return drifts found by Gen in verification mode
+func Drifts() []Drift {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Drift(nil), drifts...)
+}
+
+// ResetDrifts clear drifts found by Gen
+func ResetDrifts() {
+	mu.Lock()
+	defer mu.Unlock()
+	drifts = nil
+}
+
+// AssertNoDrift report each drift found by Gen as test failure and clear them
+func AssertNoDrift(t TestingT) {
+	for _, d := range Drifts() {
+		t.Errorf("%s", d)
+	}
+	ResetDrifts()
+}
+
+// Verify compare api with recorded api which has same version, method and path
+func (p *Project) Verify(api API) []Drift {
+	api = p.versioned(api)
+	recorded, ok := p.findRecordedAPI(api)
+	if !ok {
+		return []Drift{{
+			Method: api.RequestMethod,
+			Path:   api.RequestPath,
+			Field:  "endpoint",
+			Actual: "not documented",
+		}}
+	}
+	return compareAPI(recorded, api)
+}
+
+// findRecordedAPI find api which has same version, method, path and status code, or same version, method and path
+func (p *Project) findRecordedAPI(api API) (API, bool) {
+	var candidate *API
+	for i, recorded := range p.APIs {
+		if recorded.Version != api.Version || recorded.RequestMethod != api.RequestMethod || recorded.RequestPath != api.RequestPath {
+			continue
+		}
+		if recorded.ResponseStatusCode == api.ResponseStatusCode {
+			return recorded, true
+		}
+		if candidate == nil {
+			candidate = &p.APIs[i]
+		}
+	}
+	if candidate == nil {
+		return API{}, false
+	}
+	return *candidate, true
+}
+
+// compareAPI compare status code, response header names, content type and response body shape
+func compareAPI(expected, actual API) []Drift {
+	var found []Drift
+	add := func(field, e, a string) {
+		found = append(found, Drift{
+			Method:   actual.RequestMethod,
+			Path:     actual.RequestPath,
+			Field:    field,
+			Expected: e,
+			Actual:   a,
+		})
+	}
+
+	if expected.ResponseStatusCode != actual.ResponseStatusCode {
+		add("status code", strconv.Itoa(expected.ResponseStatusCode), strconv.Itoa(actual.ResponseStatusCode))
+	}
+
+	for _, key := range sortedKeys(expected.ResponseHeaders) {
+		if _, ok := actual.ResponseHeaders[key]; !ok {
+			add("header "+key, headerValue(expected.ResponseHeaders[key]), "")
+		}
+	}
+	for _, key := range sortedKeys(actual.ResponseHeaders) {
+		if _, ok := expected.ResponseHeaders[key]; !ok {
+			add("header "+key, "", headerValue(actual.ResponseHeaders[key]))
+		}
+	}
+	if e, a := expected.responseContentType(), actual.responseContentType(); e != a {
+		add("content type", e, a)
+	}
+
+	expectedShape, ok1 := jsonShape(expected.ResponseBody)
+	actualShape, ok2 := jsonShape(actual.ResponseBody)
+	if !ok1 || !ok2 {
+		return found
+	}
+	for _, path := range sortedKeys(expectedShape) {
+		a, ok := actualShape[path]
+		if !ok {
+			add("response body "+path, expectedShape[path], "")
+			continue
+		}
+		if a != expectedShape[path] {
+			add("response body "+path, expectedShape[path], a)
+		}
+	}
+	for _, path := range sortedKeys(actualShape) {
+		if _, ok := expectedShape[path]; !ok {
+			add("response body "+path, "", actualShape[path])
+		}
+	}
+	return found
+}
@@ -0,0 +1,80 @@
+package apidoc
+
+import (
+	"fmt"
+	"testing"
+)
+
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestProjectVerify(t *testing.T) {
+	p := Project{APIs: []API{newTestAPI()}}
+	if drifts := p.Verify(newTestAPI()); len(drifts) != 0 {
+		t.Fatalf("unexpected drifts %v", drifts)
+	}
+
+	api := newTestAPI()
+	api.ResponseStatusCode = 201
+	api.ResponseHeaders["X-New"] = "new"
+	api.ResponseBody = `{"users": [{"id": "1"}], "total": 1}`
+	want := map[string]bool{
+		"status code":                  true,
+		"header X-New":                 true,
+		"response body $.users[].id":   true,
+		"response body $.users[].name": true,
+		"response body $.total":        true,
+	}
+	drifts := p.Verify(api)
+	if len(drifts) != len(want) {
+		t.Fatalf("unexpected drifts %v", drifts)
+	}
+	for _, d := range drifts {
+		if !want[d.Field] {
+			t.Fatalf("unexpected drift %v", d)
+		}
+	}
+
+	api = newTestAPI()
+	api.RequestPath = "/items"
+	if drifts := p.Verify(api); len(drifts) != 1 || drifts[0].Field != "endpoint" {
+		t.Fatalf("unexpected drifts %v", drifts)
+	}
+}
+
+func TestGenVerification(t *testing.T) {
+	saved := p
+	defer func() {
+		p = saved
+		DisableVerification()
+		ResetDrifts()
+	}()
+	p = Project{APIs: []API{newTestAPI()}}
+	EnableVerification()
+
+	if err := Gen(newTestAPI()); err != nil {
+		t.Fatal(err)
+	}
+	api := newTestAPI()
+	api.ResponseStatusCode = 500
+	err := Gen(api)
+	if _, ok := err.(*DriftError); !ok {
+		t.Fatalf("DriftError is expected but got %v", err)
+	}
+	if len(p.APIs) != 1 || p.APIs[0].ResponseStatusCode != 200 {
+		t.Fatal("recorded apis must not be changed")
+	}
+	ft := &fakeT{}
+	AssertNoDrift(ft)
+	if len(ft.errors) != 1 {
+		t.Fatalf("unexpected errors %v", ft.errors)
+	}
+	if len(Drifts()) != 0 {
+		t.Fatal("drifts must be cleared")
+	}
+}
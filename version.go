@@ -0,0 +1,65 @@
+package apidoc
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Versioner return version of api, empty if unknown
+type Versioner func(api API) string
+
+var pathVersionPattern = regexp.MustCompile(`^v[0-9]+(\.[0-9]+)*$`)
+
+// VersionByPathPrefix version apis by first path segment like /v1/users
+func VersionByPathPrefix() Versioner {
+	return func(api API) string {
+		for _, segment := range strings.Split(api.RequestPath, "/") {
+			if segment == "" {
+				continue
+			}
+			if pathVersionPattern.MatchString(segment) {
+				return segment
+			}
+			return ""
+		}
+		return ""
+	}
+}
+
+// VersionByHeader version apis by request header like Accept-Version
+func VersionByHeader(name string) Versioner {
+	return func(api API) string {
+		return headerValue(api.RequestHeaders[name])
+	}
+}
+
+// Versions return sorted versions of apis
+func (p *Project) Versions() []string {
+	seen := map[string]bool{}
+	var versions []string
+	for _, api := range p.APIs {
+		if api.Version != "" && !seen[api.Version] {
+			seen[api.Version] = true
+			versions = append(versions, api.Version)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// ForVersion return copy of project which has apis of version only
+func (p *Project) ForVersion(version string) *Project {
+	vp := *p
+	vp.APIs = []API{}
+	vp.version = version
+	for _, api := range p.APIs {
+		if api.Version == version {
+			vp.APIs = append(vp.APIs, api)
+		}
+	}
+	if vp.DocumentTitle != "" {
+		vp.DocumentTitle += " " + version
+	}
+	return &vp
+}
@@ -0,0 +1,150 @@
+package apidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVersionByPathPrefix(t *testing.T) {
+	v := VersionByPathPrefix()
+	for path, want := range map[string]string{
+		"/v1/users":   "v1",
+		"/v2.1/users": "v2.1",
+		"/users/v1":   "",
+		"/":           "",
+	} {
+		api := NewAPI()
+		api.RequestPath = path
+		if got := v(api); got != want {
+			t.Fatalf("%s: got %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestVersionByHeader(t *testing.T) {
+	p := Project{APIs: []API{}, Versioner: VersionByHeader("Accept-Version")}
+	a1 := newTestAPI()
+	a1.RequestHeaders["Accept-Version"] = " v1\r"
+	a2 := newTestAPI()
+	a2.RequestHeaders["Accept-Version"] = " v2\r"
+	a3 := newTestAPI()
+	a3.Version = "beta"
+	p.appendAPI(a1)
+	p.appendAPI(a2)
+	p.appendAPI(a3)
+	if len(p.APIs) != 3 {
+		t.Fatal("API len is not 3")
+	}
+	versions := p.Versions()
+	if strings.Join(versions, ",") != "beta,v1,v2" {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+	vp := p.ForVersion("v2")
+	if len(vp.APIs) != 1 || vp.APIs[0].Version != "v2" {
+		t.Fatalf("unexpected apis %v", vp.APIs)
+	}
+}
+
+func TestWriteVersionOutputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	a1 := newTestAPI()
+	a1.RequestPath = "/v1/users"
+	a2 := newTestAPI()
+	a2.RequestPath = "/v2/users"
+	p := Project{
+		DocumentTitle: "apidoc-test",
+		Versioner:     VersionByPathPrefix(),
+		Outputs: []Output{
+			{Format: FormatOpenAPI, Path: filepath.Join(dir, "v1.json"), Version: "v1"},
+			{Format: FormatHTML, Path: filepath.Join(dir, "apidoc.html"), TemplatePath: "default.tpl.html"},
+		},
+		APIs: []API{},
+	}
+	p.appendAPI(a1)
+	p.appendAPI(a2)
+	if err := p.writeOutputFiles(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`"/v1/users"`)) || bytes.Contains(b, []byte(`"/v2/users"`)) {
+		t.Fatal(string(b))
+	}
+	if !bytes.Contains(b, []byte(`"version": "v1"`)) {
+		t.Fatal(string(b))
+	}
+	b, err = ioutil.ReadFile(filepath.Join(dir, "apidoc.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`<option value="v2">v2</option>`)) {
+		t.Fatal("version switcher is not rendered")
+	}
+}
+
+func TestTwoVersionsOfRoute(t *testing.T) {
+	newVersionAPI := func(version, body string) API {
+		api := newTestAPI()
+		api.RequestHeaders["Accept-Version"] = " " + version + "\r"
+		api.Version = version
+		api.ResponseBody = body
+		return api
+	}
+	v1 := newVersionAPI("v1", `{"users": []}`)
+	v2 := newVersionAPI("v2", `{"items": []}`)
+	apis := []API{v1, v2}
+
+	if errs := Validate(apis); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	v2Changed := newVersionAPI("v2", `{"items": [], "total": 0}`)
+	changes := Diff(apis, []API{v1, v2Changed})
+	if len(changes) != 1 || changes[0].Version != "v2" || changes[0].Kind != ResponseFieldAdded || !strings.HasPrefix(changes[0].String(), "v2 GET /users 200") {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+
+	p := Project{APIs: apis, Versioner: VersionByHeader("Accept-Version")}
+	live := newTestAPI()
+	live.RequestHeaders["Accept-Version"] = " v2\r"
+	live.ResponseBody = `{"items": []}`
+	if drifts := p.Verify(live); len(drifts) != 0 {
+		t.Fatalf("v2 must be compared with recorded v2 %v", drifts)
+	}
+
+	h := NewMockHandler(apis)
+	h.Versioner = VersionByHeader("Accept-Version")
+	for version, want := range map[string]string{"v1": v1.ResponseBody, "v2": v2.ResponseBody} {
+		r := httptest.NewRequest("GET", "/users?limit=30", nil)
+		r.Header.Set("Accept-Version", version)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Body.String() != want {
+			t.Fatalf("%s: got %s", version, w.Body.String())
+		}
+	}
+
+	var b bytes.Buffer
+	if err := p.WritePostman(&b); err != nil {
+		t.Fatal(err)
+	}
+	var c postmanCollection
+	if err := json.Unmarshal(b.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Item) != 1 || len(c.Item[0].Item) != 2 || c.Item[0].Item[0].Name != "GET /users v1" || c.Item[0].Item[1].Name != "GET /users v2" {
+		t.Fatalf("each version must be a request %+v", c.Item)
+	}
+}